@@ -1,27 +1,112 @@
 package cmd
 
 import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
 	"github.com/3nd3r1/kubin/cli/pkg/log"
 	"github.com/3nd3r1/kubin/cli/pkg/snapshot"
 	"github.com/spf13/cobra"
 )
 
+var (
+	createOpts          snapshot.Options
+	createAllNamespaces bool
+	createListKinds     bool
+)
+
 var createCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a snapshot of your current Kubernetes cluster",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		manager, err := snapshot.NewManager()
+		if createAllNamespaces && len(createOpts.Scope.Namespaces) > 0 {
+			return fmt.Errorf("--all-namespaces cannot be combined with --namespace")
+		}
+
+		manager, err := snapshot.NewManager(createOpts)
 		if err != nil {
 			return err
 		}
 
-        log.Info("Creating snapshot...")
+		if createListKinds {
+			return listKinds(cmd, manager)
+		}
+
+		log.Info("Creating snapshot...")
 		if err := manager.CreateSnapshot(cmd.Context()); err != nil {
-            log.WithError(err).Error("Failed to create snapshot")
+			log.WithError(err).Error("Failed to create snapshot")
 			return err
 		}
 
-        log.Info("Snapshot created")
+		fmt.Fprint(cmd.OutOrStdout(), manager.RedactionReport().Summary())
+
+		if failures := manager.Failures(); len(failures) > 0 {
+			fmt.Fprint(cmd.OutOrStdout(), failures.Summary())
+			log.Info("Snapshot created, but it is incomplete")
+			// The snapshot itself is fine, usage would only distract
+			cmd.SilenceUsage = true
+			return &exitError{
+				code: exitIncomplete,
+				err:  fmt.Errorf("snapshot is incomplete, %d parts of the cluster could not be collected", len(failures)),
+			}
+		}
+
+		log.Info("Snapshot created")
 		return nil
 	},
 }
+
+// listKinds prints every kind that can be captured from the cluster and
+// whether the kind filter selects it
+func listKinds(cmd *cobra.Command, manager *snapshot.Manager) error {
+	kinds, err := manager.ListKinds(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tGROUP\tCOLLECTOR\tCAPTURED")
+	for _, kind := range kinds {
+		group := kind.Group
+		if group == "" {
+			group = "-"
+		}
+		captured := "no"
+		if manager.Captures(kind) {
+			captured = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", kind.Kind, group, kind.Collector, captured)
+	}
+
+	return w.Flush()
+}
+
+func init() {
+	createCmd.Flags().StringArrayVarP(&createOpts.Scope.Namespaces, "namespace", "n", nil, "Only capture this namespace, glob patterns such as team-* are accepted, repeat for more namespaces")
+	createCmd.Flags().StringArrayVar(&createOpts.Scope.ExcludeNamespaces, "exclude-namespace", nil, "Leave out namespaces matching this glob pattern, repeat for more patterns")
+	createCmd.Flags().BoolVarP(&createAllNamespaces, "all-namespaces", "A", false, "Capture every namespace, this is the default")
+	createCmd.Flags().StringVarP(&createOpts.Scope.LabelSelector, "selector", "l", "", "Only capture namespaced objects matching this label selector (e.g. app=checkout)")
+	createCmd.Flags().StringVar(&createOpts.Scope.FieldSelector, "field-selector", "", "Only capture namespaced objects matching this field selector (e.g. status.phase!=Running), kinds without the field are skipped")
+	createCmd.Flags().BoolVar(&createOpts.Scope.IncludeRelated, "include-related", false, "With a selector, also capture the owners of matched pods, the services selecting them, the claims they mount and their events")
+	createCmd.Flags().StringSliceVar(&createOpts.Kinds.Include, "include-kinds", nil, "Only capture these kinds or collectors (e.g. pod,deployment or events), glob patterns are accepted")
+	createCmd.Flags().StringSliceVar(&createOpts.Kinds.Exclude, "exclude-kinds", nil, "Leave out these kinds or collectors (e.g. lease,event), glob patterns are accepted")
+	createCmd.Flags().BoolVar(&createListKinds, "list-kinds", false, "List the kinds that can be captured from the current cluster and exit")
+	createCmd.Flags().DurationVar(&createOpts.EventsSince, "events-since", 0, "Only capture events seen within this duration (e.g. 1h), 0 captures all events")
+	createCmd.Flags().Int64Var(&createOpts.LogTailLines, "log-tail-lines", 0, "Only capture the last N lines of each container log, 0 captures the whole log")
+	createCmd.Flags().DurationVar(&createOpts.LogSince, "log-since", 0, "Only capture log lines newer than this duration (e.g. 30m), 0 captures the whole log")
+	createCmd.Flags().Int64Var(&createOpts.LogLimitBytes, "log-limit-bytes", 10*1024*1024, "Maximum number of bytes captured per container log, 0 disables the limit")
+	createCmd.Flags().BoolVar(&createOpts.IncludeSecretValues, "include-secret-values", false, "Include secret values in clear text, by default only keys, sizes and salted hashes are captured")
+	createCmd.Flags().BoolVar(&createOpts.Anonymize, "anonymize", false, "Replace namespace, pod, node and host names and internal IPs with stable hashes, see kubin deanonymize")
+	createCmd.Flags().StringVar(&createOpts.Kubeconfig, "kubeconfig", "", "Path to the kubeconfig file, overrides $KUBECONFIG and ~/.kube/config")
+	createCmd.Flags().StringVar(&createOpts.Context, "context", "", "Name of the kubeconfig context to capture instead of the current one")
+	createCmd.Flags().StringVar(&createOpts.As, "as", "", "User to impersonate for every request")
+	createCmd.Flags().StringArrayVar(&createOpts.AsGroups, "as-group", nil, "Group to impersonate for every request, repeat for more groups, requires --as")
+	createCmd.Flags().IntVar(&createOpts.Concurrency, "concurrency", 10, "Maximum number of calls to the API server in flight at once, lower it for busy or small control planes")
+	createCmd.Flags().Int64Var(&createOpts.PageSize, "page-size", kube.DefaultPageSize, "Number of objects read per list call, 0 reads every list in one call")
+	createCmd.Flags().Float32Var(&createOpts.KubeQPS, "kube-qps", 5, "Maximum requests per second sent to the API server")
+	createCmd.Flags().IntVar(&createOpts.KubeBurst, "kube-burst", 10, "Maximum burst of requests sent to the API server above --kube-qps")
+	createCmd.Flags().DurationVar(&createOpts.Timeout, "timeout", 0, "Stop collecting after this duration (e.g. 10m) and write what was collected, 0 waits for every collector")
+	createCmd.Flags().DurationVar(&createOpts.CollectorTimeout, "collector-timeout", 5*time.Minute, "Stop a single collector after this duration and keep what it collected, 0 disables the limit")
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/3nd3r1/kubin/cli/pkg/config"
+	"github.com/3nd3r1/kubin/cli/pkg/redact"
+	"github.com/spf13/cobra"
+)
+
+var deanonymizeCmd = &cobra.Command{
+	Use:   "deanonymize [file]",
+	Short: "Restore the original names in text referring to an anonymized snapshot",
+	Long: "Reads text mentioning identifiers of an anonymized snapshot, such as findings from a vendor, " +
+		"from the given file or stdin and prints it with the original names restored.",
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		input := cmd.InOrStdin()
+		if len(args) == 1 {
+			file, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to open %s: %w", args[0], err)
+			}
+			defer file.Close()
+			input = file
+		}
+
+		text, err := io.ReadAll(input)
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+
+		mapping, err := config.LoadAnonymizationMapping()
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprint(cmd.OutOrStdout(), redact.Deanonymize(string(text), mapping))
+		return err
+	},
+}
@@ -0,0 +1,19 @@
+package cmd
+
+// exitIncomplete is the exit code of a snapshot that was created but misses
+// part of the cluster
+const exitIncomplete = 3
+
+// exitError makes the command exit with code instead of 1
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
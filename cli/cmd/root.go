@@ -1,6 +1,9 @@
 package cmd
 
 import (
+    "errors"
+    "os"
+
     "github.com/spf13/cobra"
 )
 
@@ -10,9 +13,17 @@ var rootCmd = &cobra.Command{
 }
 
 func Execute() {
-    cobra.CheckErr(rootCmd.Execute())
+    err := rootCmd.Execute()
+
+    var exitErr *exitError
+    if errors.As(err, &exitErr) {
+        os.Exit(exitErr.code)
+    }
+
+    cobra.CheckErr(err)
 }
 
 func init() {
     rootCmd.AddCommand(createCmd)
+    rootCmd.AddCommand(deanonymizeCmd)
 }
@@ -0,0 +1,192 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+// ClusterInfo identifies the cluster a snapshot was taken from
+type ClusterInfo struct {
+	Context       string         `json:"context"`
+	ClusterName   string         `json:"clusterName"`
+	Server        string         `json:"server"`
+	ServerVersion *version.Info  `json:"serverVersion"`
+	Provider      string         `json:"provider"`
+	APIGroups     []APIGroupInfo `json:"apiGroups"`
+	Nodes         []NodeInfo     `json:"nodes"`
+	CollectedAt   time.Time      `json:"collectedAt"`
+}
+
+type APIGroupInfo struct {
+	Name             string   `json:"name"`
+	Versions         []string `json:"versions"`
+	PreferredVersion string   `json:"preferredVersion"`
+}
+
+type NodeInfo struct {
+	Name                    string `json:"name"`
+	ProviderID              string `json:"providerID,omitempty"`
+	OSImage                 string `json:"osImage"`
+	OperatingSystem         string `json:"operatingSystem"`
+	Architecture            string `json:"architecture"`
+	KernelVersion           string `json:"kernelVersion"`
+	KubeletVersion          string `json:"kubeletVersion"`
+	ContainerRuntimeVersion string `json:"containerRuntimeVersion"`
+}
+
+// ClusterInfoCollector gathers the identity of the cluster rather than its
+// resources, so it is run once per snapshot instead of as a Collector
+type ClusterInfoCollector struct {
+	client kube.Client
+	now    func() time.Time
+}
+
+func NewClusterInfoCollector(client kube.Client) *ClusterInfoCollector {
+	return &ClusterInfoCollector{client: client, now: time.Now}
+}
+
+// Collect returns what could be read about the cluster, along with the
+// failures of the parts that could not. Users limited to some namespaces
+// are often not allowed to list nodes
+func (c *ClusterInfoCollector) Collect(ctx context.Context) (*ClusterInfo, error) {
+	kubeContext := c.client.GetKubeContext()
+	info := &ClusterInfo{
+		Context:     kubeContext.Context,
+		ClusterName: kubeContext.Cluster,
+		Server:      kubeContext.Server,
+		CollectedAt: c.now().UTC(),
+	}
+
+	var p partial
+
+	serverVersion, err := c.client.GetServerVersion(ctx)
+	if err != nil {
+		if err := p.tolerate(fmt.Errorf("failed to get server version: %w", err), "", ""); err != nil {
+			return nil, err
+		}
+	}
+	info.ServerVersion = serverVersion
+
+	groups, err := c.client.GetServerGroups(ctx)
+	if err != nil {
+		if err := p.tolerate(fmt.Errorf("failed to get api groups: %w", err), "", ""); err != nil {
+			return nil, err
+		}
+		groups = &metav1.APIGroupList{}
+	}
+
+	for _, group := range groups.Groups {
+		groupInfo := APIGroupInfo{
+			Name:             group.Name,
+			PreferredVersion: group.PreferredVersion.Version,
+		}
+		for _, groupVersion := range group.Versions {
+			groupInfo.Versions = append(groupInfo.Versions, groupVersion.Version)
+		}
+		info.APIGroups = append(info.APIGroups, groupInfo)
+	}
+
+	nodes, err := c.client.GetNodes(ctx, metav1.ListOptions{})
+	if err != nil {
+		if err := p.tolerate(fmt.Errorf("failed to get nodes: %w", err), "node", ""); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	for _, node := range nodes {
+		info.Nodes = append(info.Nodes, NodeInfo{
+			Name:                    node.Name,
+			ProviderID:              node.Spec.ProviderID,
+			OSImage:                 node.Status.NodeInfo.OSImage,
+			OperatingSystem:         node.Status.NodeInfo.OperatingSystem,
+			Architecture:            node.Status.NodeInfo.Architecture,
+			KernelVersion:           node.Status.NodeInfo.KernelVersion,
+			KubeletVersion:          node.Status.NodeInfo.KubeletVersion,
+			ContainerRuntimeVersion: node.Status.NodeInfo.ContainerRuntimeVersion,
+		})
+	}
+
+	info.Provider = detectProvider(nodes, info.APIGroups, serverVersion)
+
+	for _, failure := range p.failures {
+		failure.Collector = "clusterinfo"
+	}
+	_, err = p.result()
+
+	return info, err
+}
+
+// detectProvider makes a best effort guess of the distribution or cloud the
+// cluster runs on from node labels, provider IDs and served API groups
+func detectProvider(nodes []corev1.Node, groups []APIGroupInfo, serverVersion *version.Info) string {
+	for _, group := range groups {
+		if strings.HasSuffix(group.Name, ".openshift.io") {
+			return "openshift"
+		}
+	}
+
+	if serverVersion != nil {
+		switch {
+		case strings.Contains(serverVersion.GitVersion, "-eks-"):
+			return "eks"
+		case strings.Contains(serverVersion.GitVersion, "-gke."):
+			return "gke"
+		case strings.Contains(serverVersion.GitVersion, "+k3s"):
+			return "k3s"
+		}
+	}
+
+	for _, node := range nodes {
+		if provider := detectNodeProvider(node); provider != "" {
+			return provider
+		}
+	}
+
+	return "unknown"
+}
+
+func detectNodeProvider(node corev1.Node) string {
+	hasLabelPrefix := func(prefix string) bool {
+		for label := range node.Labels {
+			if strings.HasPrefix(label, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+
+	providerID := node.Spec.ProviderID
+	switch {
+	case hasLabelPrefix("eks.amazonaws.com/"):
+		return "eks"
+	case hasLabelPrefix("cloud.google.com/gke-"):
+		return "gke"
+	case hasLabelPrefix("kubernetes.azure.com/"):
+		return "aks"
+	case hasLabelPrefix("node.openshift.io/"):
+		return "openshift"
+	case strings.HasPrefix(providerID, "kind://"):
+		return "kind"
+	case strings.HasPrefix(providerID, "k3s://"), strings.Contains(node.Status.NodeInfo.KubeletVersion, "+k3s"):
+		return "k3s"
+	case hasLabelPrefix("minikube.k8s.io/"):
+		return "minikube"
+	case strings.HasPrefix(providerID, "aws://"):
+		return "aws"
+	case strings.HasPrefix(providerID, "gce://"):
+		return "gce"
+	case strings.HasPrefix(providerID, "azure://"):
+		return "azure"
+	}
+
+	return ""
+}
@@ -0,0 +1,170 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+func TestClusterInfoCollector_Collect_Success(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetKubeContextFunc: func() kube.KubeContext {
+			return kube.KubeContext{Context: "prod", Cluster: "prod-cluster", Server: "https://10.0.0.1:6443"}
+		},
+		GetServerVersionFunc: func(ctx context.Context) (*version.Info, error) {
+			return &version.Info{GitVersion: "v1.33.2"}, nil
+		},
+		GetServerGroupsFunc: func(ctx context.Context) (*metav1.APIGroupList, error) {
+			return &metav1.APIGroupList{
+				Groups: []metav1.APIGroup{
+					{
+						Name:             "apps",
+						Versions:         []metav1.GroupVersionForDiscovery{{GroupVersion: "apps/v1", Version: "v1"}},
+						PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "apps/v1", Version: "v1"},
+					},
+				},
+			}, nil
+		},
+		GetNodesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Node, error) {
+			return []corev1.Node{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "worker"},
+					Spec:       corev1.NodeSpec{ProviderID: "kind://docker/kind/worker"},
+					Status: corev1.NodeStatus{
+						NodeInfo: corev1.NodeSystemInfo{
+							OSImage:                 "Debian GNU/Linux 12 (bookworm)",
+							KernelVersion:           "6.8.0",
+							KubeletVersion:          "v1.33.2",
+							ContainerRuntimeVersion: "containerd://2.1.1",
+							OperatingSystem:         "linux",
+							Architecture:            "amd64",
+						},
+					},
+				},
+				{ObjectMeta: metav1.ObjectMeta{Name: "control-plane"}},
+			}, nil
+		},
+	}
+
+	collector := NewClusterInfoCollector(mockClient)
+	collector.now = func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) }
+	info, err := collector.Collect(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "prod", info.Context)
+	assert.Equal(t, "prod-cluster", info.ClusterName)
+	assert.Equal(t, "https://10.0.0.1:6443", info.Server)
+	assert.Equal(t, "v1.33.2", info.ServerVersion.GitVersion)
+	assert.Equal(t, "kind", info.Provider)
+	assert.Equal(t, []APIGroupInfo{{Name: "apps", Versions: []string{"v1"}, PreferredVersion: "v1"}}, info.APIGroups)
+	assert.Len(t, info.Nodes, 2)
+	assert.Equal(t, "control-plane", info.Nodes[0].Name)
+	assert.Equal(t, "containerd://2.1.1", info.Nodes[1].ContainerRuntimeVersion)
+}
+
+func TestClusterInfoCollector_Collect_VersionError(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetKubeContextFunc: func() kube.KubeContext {
+			return kube.KubeContext{Context: "prod", Cluster: "prod-cluster", Server: "https://10.0.0.1:6443"}
+		},
+		GetServerVersionFunc: func(ctx context.Context) (*version.Info, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	collector := NewClusterInfoCollector(mockClient)
+	info, err := collector.Collect(context.Background())
+
+	assert.Error(t, err)
+	assert.Nil(t, info)
+	assert.Contains(t, err.Error(), "failed to get server version")
+}
+
+func TestClusterInfoCollector_Collect_NodesForbidden(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetKubeContextFunc: func() kube.KubeContext {
+			return kube.KubeContext{Context: "prod", Cluster: "prod-cluster", Server: "https://10.0.0.1:6443"}
+		},
+		GetServerVersionFunc: func(ctx context.Context) (*version.Info, error) {
+			return &version.Info{GitVersion: "v1.33.2"}, nil
+		},
+		GetServerGroupsFunc: func(ctx context.Context) (*metav1.APIGroupList, error) {
+			return &metav1.APIGroupList{}, nil
+		},
+		GetNodesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Node, error) {
+			return nil, apierrors.NewForbidden(corev1.Resource("nodes"), "", errors.New("namespace scoped user"))
+		},
+	}
+
+	collector := NewClusterInfoCollector(mockClient)
+	info, err := collector.Collect(context.Background())
+
+	// Everything but the nodes is still known
+	assert.Equal(t, "prod", info.Context)
+	assert.Equal(t, "v1.33.2", info.ServerVersion.GitVersion)
+	assert.Empty(t, info.Nodes)
+	var failures Failures
+	assert.ErrorAs(t, err, &failures)
+	assert.Len(t, failures, 1)
+	assert.Equal(t, "clusterinfo", failures[0].Collector)
+	assert.Equal(t, "node", failures[0].Kind)
+	assert.Equal(t, "Forbidden", failures[0].Reason)
+}
+
+func TestDetectProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     corev1.Node
+		groups   []APIGroupInfo
+		expected string
+	}{
+		{
+			name: "eks",
+			node: corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"eks.amazonaws.com/nodegroup": "default"}},
+				Spec:       corev1.NodeSpec{ProviderID: "aws:///eu-west-1a/i-0123"},
+			},
+			expected: "eks",
+		},
+		{
+			name:     "gke",
+			node:     corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"cloud.google.com/gke-nodepool": "pool"}}},
+			expected: "gke",
+		},
+		{
+			name:     "aks",
+			node:     corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"kubernetes.azure.com/cluster": "mc_rg"}}},
+			expected: "aks",
+		},
+		{
+			name: "k3s",
+			node: corev1.Node{
+				Status: corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{KubeletVersion: "v1.33.2+k3s1"}},
+			},
+			expected: "k3s",
+		},
+		{
+			name:     "openshift",
+			groups:   []APIGroupInfo{{Name: "config.openshift.io"}},
+			expected: "openshift",
+		},
+		{
+			name:     "unknown",
+			expected: "unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, detectProvider([]corev1.Node{tt.node}, tt.groups, nil))
+		})
+	}
+}
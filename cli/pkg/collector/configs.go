@@ -0,0 +1,162 @@
+package collector
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	"github.com/3nd3r1/kubin/cli/pkg/redact"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConfigOptions controls how much of each secret is captured
+type ConfigOptions struct {
+	// IncludeSecretValues captures secrets in full, values included
+	IncludeSecretValues bool
+	// SecretSalt keys the value hashes, the same salt gives the same hash
+	// for the same value across snapshots
+	SecretSalt []byte
+}
+
+// SecretSummary describes a secret without revealing its values
+type SecretSummary struct {
+	metav1.ObjectMeta `json:"metadata"`
+
+	Type corev1.SecretType `json:"type"`
+	Keys []SecretKey       `json:"keys"`
+}
+
+type SecretKey struct {
+	Name string `json:"name"`
+	Size int    `json:"size"`
+	// Hash is the salted HMAC-SHA256 of the value
+	Hash string `json:"hash"`
+}
+
+// ConfigCollector collects configmaps in full and secrets as summaries
+// unless their values are explicitly requested
+type ConfigCollector struct {
+	client kube.Client
+	kinds  KindFilter
+	opts   ConfigOptions
+}
+
+func NewConfigCollector(client kube.Client, kinds KindFilter, opts ConfigOptions) *ConfigCollector {
+	return &ConfigCollector{client: client, kinds: kinds, opts: opts}
+}
+
+func (c *ConfigCollector) Name() string {
+	return "config"
+}
+
+func (c *ConfigCollector) Kinds() []string {
+	return []string{"configmap", "secret"}
+}
+
+func (c *ConfigCollector) Collect(ctx context.Context) ([]ClusterResource, error) {
+	namespaces, err := c.client.GetNamespaces(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get namespaces for config collection: %w", err), "namespace", "")
+	}
+
+	return collectEach(ctx, namespaces, c.collectNamespace)
+}
+
+func (c *ConfigCollector) collectNamespace(ctx context.Context, namespace corev1.Namespace) ([]ClusterResource, error) {
+	var resources []ClusterResource
+
+	// Reading secrets is often forbidden where configmaps are not, either
+	// failing leaves the other
+	var p partial
+	var err error
+
+	var configMaps []corev1.ConfigMap
+	if c.kinds.Matches(c.Name(), "configmap") {
+		configMaps, err = c.client.GetConfigMaps(ctx, namespace.Name, metav1.ListOptions{})
+		if err != nil {
+			if err := p.tolerate(fmt.Errorf("failed to get configmaps from namespace %s: %w", namespace.Name, err), "configmap", namespace.Name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, configMap := range configMaps {
+		resources = append(resources, ClusterResource{
+			Kind:    "configmap",
+			Version: "v1",
+			Name:    configMap.Name,
+			Data:    configMap,
+			Metadata: map[string]string{
+				"namespace": namespace.Name,
+				"keys":      strconv.Itoa(len(configMap.Data) + len(configMap.BinaryData)),
+			},
+		})
+	}
+
+	var secrets []corev1.Secret
+	if c.kinds.Matches(c.Name(), "secret") {
+		secrets, err = c.client.GetSecrets(ctx, namespace.Name, metav1.ListOptions{})
+		if err != nil {
+			if err := p.tolerate(fmt.Errorf("failed to get secrets from namespace %s: %w", namespace.Name, err), "secret", namespace.Name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, secret := range secrets {
+		var data interface{} = c.secretSummary(secret)
+		if c.opts.IncludeSecretValues {
+			data = secret
+		}
+
+		resources = append(resources, ClusterResource{
+			Kind:    "secret",
+			Version: "v1",
+			Name:    secret.Name,
+			Data:    data,
+			Metadata: map[string]string{
+				"namespace":      namespace.Name,
+				"type":           string(secret.Type),
+				"keys":           strconv.Itoa(len(secret.Data)),
+				"valuesIncluded": strconv.FormatBool(c.opts.IncludeSecretValues),
+			},
+		})
+	}
+
+	p.resources = resources
+	return p.result()
+}
+
+// secretSummary keeps the metadata of a secret and replaces every value by
+// its size and salted hash
+func (c *ConfigCollector) secretSummary(secret corev1.Secret) SecretSummary {
+	meta := *secret.ObjectMeta.DeepCopy()
+	meta.ManagedFields = nil
+	delete(meta.Annotations, redact.LastAppliedAnnotation)
+
+	summary := SecretSummary{
+		ObjectMeta: meta,
+		Type:       secret.Type,
+		Keys:       []SecretKey{},
+	}
+
+	for name, value := range secret.Data {
+		mac := hmac.New(sha256.New, c.opts.SecretSalt)
+		mac.Write(value)
+
+		summary.Keys = append(summary.Keys, SecretKey{
+			Name: name,
+			Size: len(value),
+			Hash: hex.EncodeToString(mac.Sum(nil)),
+		})
+	}
+	sort.Slice(summary.Keys, func(i, j int) bool { return summary.Keys[i].Name < summary.Keys[j].Name })
+
+	return summary
+}
@@ -0,0 +1,140 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	"github.com/3nd3r1/kubin/cli/pkg/redact"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// dbSecret is a secret whose last applied configuration repeats its values
+func dbSecret(namespace string) corev1.Secret {
+	return corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "db",
+			Namespace:   namespace,
+			Annotations: map[string]string{redact.LastAppliedAnnotation: `{"data":{"password":"aHVudGVyMg=="}}`},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			"username": []byte("admin"),
+			"password": []byte("hunter2"),
+		},
+	}
+}
+
+func TestConfigCollector_Name(t *testing.T) {
+	mockClient := &kube.MockClient{}
+	collector := NewConfigCollector(mockClient, KindFilter{}, ConfigOptions{})
+
+	assert.Equal(t, "config", collector.Name())
+}
+
+func TestConfigCollector_Collect_SecretSummary(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		GetConfigMapsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.ConfigMap, error) {
+			return []corev1.ConfigMap{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: namespace},
+					Data:       map[string]string{"LOG_LEVEL": "debug"},
+				},
+			}, nil
+		},
+		GetSecretsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Secret, error) {
+			return []corev1.Secret{dbSecret(namespace)}, nil
+		},
+	}
+
+	collector := NewConfigCollector(mockClient, KindFilter{}, ConfigOptions{SecretSalt: []byte("salt")})
+	resources, err := collector.Collect(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, resources, 2)
+
+	configMap := resources[0]
+	assert.Equal(t, "configmap", configMap.Kind)
+	assert.Equal(t, "debug", configMap.Data.(corev1.ConfigMap).Data["LOG_LEVEL"])
+
+	secret := resources[1]
+	assert.Equal(t, "secret", secret.Kind)
+	assert.Equal(t, "Opaque", secret.Metadata["type"])
+	assert.Equal(t, "false", secret.Metadata["valuesIncluded"])
+
+	summary := secret.Data.(SecretSummary)
+	assert.NotContains(t, summary.Annotations, redact.LastAppliedAnnotation)
+	assert.Len(t, summary.Keys, 2)
+	assert.Equal(t, "password", summary.Keys[0].Name)
+	assert.Equal(t, 7, summary.Keys[0].Size)
+	assert.Len(t, summary.Keys[0].Hash, 64)
+	assert.NotContains(t, summary.Keys[0].Hash, "hunter2")
+
+	// The original secret is left untouched
+	original, _ := mockClient.GetSecrets(context.Background(), "default", metav1.ListOptions{})
+	assert.Contains(t, original[0].Annotations, redact.LastAppliedAnnotation)
+}
+
+func TestConfigCollector_Collect_StableHash(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		GetSecretsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Secret, error) {
+			return []corev1.Secret{dbSecret(namespace)}, nil
+		},
+	}
+
+	hash := func(salt string) string {
+		collector := NewConfigCollector(mockClient, KindFilter{Include: []string{"secret"}}, ConfigOptions{SecretSalt: []byte(salt)})
+		resources, err := collector.Collect(context.Background())
+		assert.NoError(t, err)
+		return resources[0].Data.(SecretSummary).Keys[0].Hash
+	}
+
+	assert.Equal(t, hash("salt"), hash("salt"))
+	assert.NotEqual(t, hash("salt"), hash("other"))
+}
+
+func TestConfigCollector_Collect_IncludeSecretValues(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		GetSecretsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Secret, error) {
+			return []corev1.Secret{dbSecret(namespace)}, nil
+		},
+	}
+
+	collector := NewConfigCollector(mockClient, KindFilter{Include: []string{"secret"}}, ConfigOptions{IncludeSecretValues: true})
+	resources, err := collector.Collect(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, resources, 1)
+	assert.Equal(t, "true", resources[0].Metadata["valuesIncluded"])
+	assert.Equal(t, []byte("hunter2"), resources[0].Data.(corev1.Secret).Data["password"])
+}
+
+func TestConfigCollector_Collect_SecretError(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		GetSecretsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Secret, error) {
+			return nil, errors.New("forbidden")
+		},
+	}
+
+	collector := NewConfigCollector(mockClient, KindFilter{Include: []string{"secret"}}, ConfigOptions{})
+	resources, err := collector.Collect(context.Background())
+
+	assert.Error(t, err)
+	assert.Nil(t, resources)
+	assert.Contains(t, err.Error(), "failed to get secrets from namespace default")
+}
@@ -6,46 +6,47 @@ import (
 	"fmt"
 
 	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type CoreCollector struct {
 	client kube.Client
+	kinds  KindFilter
 }
 
-func NewCoreCollector(client kube.Client) *CoreCollector {
-	return &CoreCollector{client: client}
+func NewCoreCollector(client kube.Client, kinds KindFilter) *CoreCollector {
+	return &CoreCollector{client: client, kinds: kinds}
 }
 
 func (c *CoreCollector) Name() string {
 	return "core"
 }
 
-func (c *CoreCollector) Collect(ctx context.Context) ([]ClusterResource, error) {
-	var resources []ClusterResource
+func (c *CoreCollector) Kinds() []string {
+	return []string{"namespace", "pod"}
+}
 
-	namespaces, err := c.collectNamespaces(ctx)
+func (c *CoreCollector) Collect(ctx context.Context) ([]ClusterResource, error) {
+	namespaces, err := c.client.GetNamespaces(ctx, metav1.ListOptions{})
 	if err != nil {
-		return nil, err
+		return nil, tolerate(fmt.Errorf("failed to get namespaces: %w", err), "namespace", "")
 	}
-	resources = append(resources, namespaces...)
 
-	pods, err := c.collectPods(ctx)
-	if err != nil {
+	p := partial{resources: c.collectNamespaces(namespaces)}
+	if !c.kinds.Matches(c.Name(), "pod") {
+		return p.result()
+	}
+	if err := p.add(collectEach(ctx, namespaces, c.collectPods)); err != nil {
 		return nil, err
 	}
-	resources = append(resources, pods...)
 
-	return resources, nil
+	return p.result()
 }
 
-func (c *CoreCollector) collectNamespaces(ctx context.Context) ([]ClusterResource, error) {
+func (c *CoreCollector) collectNamespaces(namespaces []corev1.Namespace) []ClusterResource {
 	var resources []ClusterResource
 
-	namespaces, err := c.client.GetNamespaces(ctx)
-	if err != nil {
-		return nil, err
-	}
-
 	for _, namespace := range namespaces {
 		resources = append(resources, ClusterResource{
 			Kind:     "namespace",
@@ -55,35 +56,28 @@ func (c *CoreCollector) collectNamespaces(ctx context.Context) ([]ClusterResourc
 		})
 	}
 
-	return resources, nil
+	return resources
 }
 
-func (c *CoreCollector) collectPods(ctx context.Context) ([]ClusterResource, error) {
+func (c *CoreCollector) collectPods(ctx context.Context, namespace corev1.Namespace) ([]ClusterResource, error) {
 	var resources []ClusterResource
 
-	namespaces, err := c.client.GetNamespaces(ctx)
+	pods, err := c.client.GetPods(ctx, namespace.Name, metav1.ListOptions{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get namespaces for pod collection: %w", err)
+		return nil, tolerate(fmt.Errorf("failed to get pods from namespace %s: %w", namespace.Name, err), "pod", namespace.Name)
 	}
 
-	for _, namespace := range namespaces {
-		pods, err := c.client.GetPods(ctx, namespace.Name)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get pods from namespace %s: %w", namespace.Name, err)
+	for _, pod := range pods {
+		metadata := map[string]string{
+			"namespace": pod.Namespace,
 		}
 
-		for _, pod := range pods {
-			metadata := map[string]string{
-				"namespace": pod.Namespace,
-			}
-
-			resources = append(resources, ClusterResource{
-				Kind:     "pod",
-				Name:     pod.Name,
-				Data:     pod,
-				Metadata: metadata,
-			})
-		}
+		resources = append(resources, ClusterResource{
+			Kind:     "pod",
+			Name:     pod.Name,
+			Data:     pod,
+			Metadata: metadata,
+		})
 	}
 
 	return resources, nil
@@ -8,12 +8,14 @@ import (
 	"github.com/3nd3r1/kubin/cli/pkg/kube"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func TestCoreCollector_Name(t *testing.T) {
 	mockClient := &kube.MockClient{}
-	collector := NewCoreCollector(mockClient)
+	collector := NewCoreCollector(mockClient, KindFilter{})
 
 	assert.Equal(t, "core", collector.Name())
 }
@@ -60,10 +62,10 @@ func TestCoreCollector_Collect_Success(t *testing.T) {
 
 	// Create mock client
 	mockClient := &kube.MockClient{
-		GetNamespacesFunc: func(ctx context.Context) ([]corev1.Namespace, error) {
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
 			return testNamespaces, nil
 		},
-		GetPodsFunc: func(ctx context.Context, namespace string) ([]corev1.Pod, error) {
+		GetPodsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
 			if pods, exists := testPods[namespace]; exists {
 				return pods, nil
 			}
@@ -71,7 +73,7 @@ func TestCoreCollector_Collect_Success(t *testing.T) {
 		},
 	}
 
-	collector := NewCoreCollector(mockClient)
+	collector := NewCoreCollector(mockClient, KindFilter{})
 	resources, err := collector.Collect(context.Background())
 
 	// Assertions
@@ -115,12 +117,12 @@ func TestCoreCollector_Collect_Success(t *testing.T) {
 func TestCoreCollector_Collect_NamespaceError(t *testing.T) {
 	// Create mock client that returns error for namespaces
 	mockClient := &kube.MockClient{
-		GetNamespacesFunc: func(ctx context.Context) ([]corev1.Namespace, error) {
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
 			return nil, errors.New("failed to connect to cluster")
 		},
 	}
 
-	collector := NewCoreCollector(mockClient)
+	collector := NewCoreCollector(mockClient, KindFilter{})
 	resources, err := collector.Collect(context.Background())
 
 	// Should fail early on namespace collection
@@ -141,15 +143,15 @@ func TestCoreCollector_Collect_PodError(t *testing.T) {
 
 	// Create mock client that returns error for pods
 	mockClient := &kube.MockClient{
-		GetNamespacesFunc: func(ctx context.Context) ([]corev1.Namespace, error) {
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
 			return testNamespaces, nil
 		},
-		GetPodsFunc: func(ctx context.Context, namespace string) ([]corev1.Pod, error) {
+		GetPodsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
 			return nil, errors.New("failed to get pods")
 		},
 	}
 
-	collector := NewCoreCollector(mockClient)
+	collector := NewCoreCollector(mockClient, KindFilter{})
 	resources, err := collector.Collect(context.Background())
 
 	// Should fail on pod collection
@@ -198,10 +200,10 @@ func TestCoreCollector_CollectPods_Success(t *testing.T) {
 	}
 
 	mockClient := &kube.MockClient{
-		GetNamespacesFunc: func(ctx context.Context) ([]corev1.Namespace, error) {
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
 			return testNamespaces, nil
 		},
-		GetPodsFunc: func(ctx context.Context, namespace string) ([]corev1.Pod, error) {
+		GetPodsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
 			if pods, exists := testPods[namespace]; exists {
 				return pods, nil
 			}
@@ -209,8 +211,8 @@ func TestCoreCollector_CollectPods_Success(t *testing.T) {
 		},
 	}
 
-	collector := NewCoreCollector(mockClient)
-	podResources, err := collector.collectPods(context.Background())
+	collector := NewCoreCollector(mockClient, KindFilter{})
+	podResources, err := collectEach(context.Background(), testNamespaces, collector.collectPods)
 
 	// Assertions
 	assert.NoError(t, err)
@@ -241,19 +243,68 @@ func TestCoreCollector_CollectPods_EmptyNamespace(t *testing.T) {
 	}
 
 	mockClient := &kube.MockClient{
-		GetNamespacesFunc: func(ctx context.Context) ([]corev1.Namespace, error) {
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
 			return testNamespaces, nil
 		},
-		GetPodsFunc: func(ctx context.Context, namespace string) ([]corev1.Pod, error) {
+		GetPodsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
 			// Return empty pod list for the namespace
 			return []corev1.Pod{}, nil
 		},
 	}
 
-	collector := NewCoreCollector(mockClient)
-	podResources, err := collector.collectPods(context.Background())
+	collector := NewCoreCollector(mockClient, KindFilter{})
+	podResources, err := collectEach(context.Background(), testNamespaces, collector.collectPods)
 
 	// Should succeed with no pods
 	assert.NoError(t, err)
 	assert.Len(t, podResources, 0)
 }
+
+func TestCoreCollector_Collect_ForbiddenNamespace(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{
+				{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "restricted"}},
+			}, nil
+		},
+		GetPodsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
+			if namespace == "restricted" {
+				return nil, apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("no access"))
+			}
+			return []corev1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: namespace}}}, nil
+		},
+	}
+
+	collector := NewCoreCollector(mockClient, KindFilter{})
+	resources, err := collector.Collect(context.Background())
+
+	// The other namespace is still collected
+	var failures Failures
+	assert.ErrorAs(t, err, &failures)
+	assert.Len(t, failures, 1)
+	assert.Equal(t, "pod", failures[0].Kind)
+	assert.Equal(t, "restricted", failures[0].Namespace)
+	assert.Equal(t, "Forbidden", failures[0].Reason)
+	assert.Len(t, resources, 3)
+}
+
+func TestCoreCollector_Collect_SkipsExcludedPods(t *testing.T) {
+	listed := false
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		GetPodsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
+			listed = true
+			return []corev1.Pod{}, nil
+		},
+	}
+
+	collector := NewCoreCollector(mockClient, KindFilter{Exclude: []string{"pod"}})
+	resources, err := collector.Collect(context.Background())
+
+	assert.NoError(t, err)
+	assert.False(t, listed)
+	assert.Len(t, resources, 1)
+}
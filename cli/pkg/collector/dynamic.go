@@ -0,0 +1,186 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// skippedResources are either collected by a typed collector or must never
+// be captured verbatim
+var skippedResources = map[schema.GroupResource]bool{
+	{Group: "", Resource: "namespaces"}: true,
+	{Group: "", Resource: "pods"}:       true,
+	{Group: "", Resource: "secrets"}:    true,
+	{Group: "", Resource: "configmaps"}: true,
+
+	{Group: "apps", Resource: "deployments"}:  true,
+	{Group: "apps", Resource: "statefulsets"}: true,
+	{Group: "apps", Resource: "daemonsets"}:   true,
+	{Group: "apps", Resource: "replicasets"}:  true,
+	{Group: "batch", Resource: "jobs"}:        true,
+	{Group: "batch", Resource: "cronjobs"}:    true,
+
+	{Group: "", Resource: "services"}:                            true,
+	{Group: "", Resource: "endpoints"}:                           true,
+	{Group: "discovery.k8s.io", Resource: "endpointslices"}:      true,
+	{Group: "networking.k8s.io", Resource: "ingresses"}:          true,
+	{Group: "networking.k8s.io", Resource: "ingressclasses"}:     true,
+	{Group: "networking.k8s.io", Resource: "networkpolicies"}:    true,
+	{Group: "gateway.networking.k8s.io", Resource: "gateways"}:   true,
+	{Group: "gateway.networking.k8s.io", Resource: "httproutes"}: true,
+
+	{Group: "", Resource: "persistentvolumes"}:               true,
+	{Group: "", Resource: "persistentvolumeclaims"}:          true,
+	{Group: "storage.k8s.io", Resource: "storageclasses"}:    true,
+	{Group: "storage.k8s.io", Resource: "volumeattachments"}: true,
+	{Group: "storage.k8s.io", Resource: "csidrivers"}:        true,
+	{Group: "storage.k8s.io", Resource: "csinodes"}:          true,
+
+	{Group: "", Resource: "events"}:              true,
+	{Group: "events.k8s.io", Resource: "events"}: true,
+
+	{Group: "", Resource: "nodes"}: true,
+
+	{Group: "metrics.k8s.io", Resource: "pods"}:  true,
+	{Group: "metrics.k8s.io", Resource: "nodes"}: true,
+
+	{Group: "", Resource: "serviceaccounts"}:                              true,
+	{Group: "rbac.authorization.k8s.io", Resource: "roles"}:               true,
+	{Group: "rbac.authorization.k8s.io", Resource: "rolebindings"}:        true,
+	{Group: "rbac.authorization.k8s.io", Resource: "clusterroles"}:        true,
+	{Group: "rbac.authorization.k8s.io", Resource: "clusterrolebindings"}: true,
+}
+
+// DynamicCollector uses API discovery to collect every listable resource in
+// the cluster, including custom resources
+type DynamicCollector struct {
+	client kube.Client
+	// kinds is applied before listing, some kinds have far too many objects
+	// to list only to throw them away
+	kinds KindFilter
+}
+
+func NewDynamicCollector(client kube.Client, kinds KindFilter) *DynamicCollector {
+	return &DynamicCollector{client: client, kinds: kinds}
+}
+
+func (c *DynamicCollector) Name() string {
+	return "dynamic"
+}
+
+func (c *DynamicCollector) Kinds() []string {
+	return nil
+}
+
+// discoveredResource is a listable resource found through discovery
+type discoveredResource struct {
+	gvr         schema.GroupVersionResource
+	apiResource metav1.APIResource
+}
+
+func (c *DynamicCollector) discover(ctx context.Context) ([]discoveredResource, error) {
+	var discovered []discoveredResource
+
+	lists, err := c.client.GetPreferredResources(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover api resources: %w", err)
+	}
+
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse group version %s: %w", list.GroupVersion, err)
+		}
+
+		for _, apiResource := range list.APIResources {
+			// Subresources such as pods/log are served under their parent
+			if strings.Contains(apiResource.Name, "/") {
+				continue
+			}
+			if !slices.Contains(apiResource.Verbs, "list") {
+				continue
+			}
+			gvr := gv.WithResource(apiResource.Name)
+			if skippedResources[gvr.GroupResource()] {
+				continue
+			}
+
+			discovered = append(discovered, discoveredResource{gvr: gvr, apiResource: apiResource})
+		}
+	}
+
+	return discovered, nil
+}
+
+// DiscoverKinds lists the kinds the collector would capture, ignoring its
+// kind filter
+func (c *DynamicCollector) DiscoverKinds(ctx context.Context) ([]KindInfo, error) {
+	discovered, err := c.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	kinds := make([]KindInfo, 0, len(discovered))
+	for _, resource := range discovered {
+		kinds = append(kinds, KindInfo{
+			Kind:      strings.ToLower(resource.apiResource.Kind),
+			Collector: c.Name(),
+			Group:     resource.gvr.Group,
+		})
+	}
+
+	return kinds, nil
+}
+
+func (c *DynamicCollector) Collect(ctx context.Context) ([]ClusterResource, error) {
+	var resources []ClusterResource
+	var p partial
+
+	discovered, err := c.discover(ctx)
+	if err != nil {
+		return nil, tolerate(err, "apiresource", "")
+	}
+
+	for _, resource := range discovered {
+		gvr := resource.gvr
+		kind := strings.ToLower(resource.apiResource.Kind)
+		if !c.kinds.Matches(c.Name(), kind) {
+			continue
+		}
+
+		items, err := c.client.ListResources(ctx, gvr, resource.apiResource.Namespaced, "", metav1.ListOptions{})
+		if err != nil {
+			if err := p.tolerate(fmt.Errorf("failed to list %s: %w", gvr.String(), err), kind, ""); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		for _, item := range items {
+			var metadata map[string]string
+			if resource.apiResource.Namespaced {
+				metadata = map[string]string{
+					"namespace": item.GetNamespace(),
+				}
+			}
+
+			resources = append(resources, ClusterResource{
+				Kind:     kind,
+				Group:    gvr.Group,
+				Version:  gvr.Version,
+				Name:     item.GetName(),
+				Data:     item.Object,
+				Metadata: metadata,
+			})
+		}
+	}
+
+	p.resources = resources
+	return p.result()
+}
@@ -0,0 +1,205 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func newUnstructured(apiVersion, kind, namespace, name string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func TestDynamicCollector_Name(t *testing.T) {
+	mockClient := &kube.MockClient{}
+	collector := NewDynamicCollector(mockClient, KindFilter{})
+
+	assert.Equal(t, "dynamic", collector.Name())
+}
+
+func TestDynamicCollector_Collect_Success(t *testing.T) {
+	testResourceLists := []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "pods", Kind: "Pod", Namespaced: true, Verbs: []string{"get", "list"}},
+				{Name: "pods/log", Kind: "Pod", Namespaced: true, Verbs: []string{"get", "list"}},
+				{Name: "secrets", Kind: "Secret", Namespaced: true, Verbs: []string{"get", "list"}},
+				{Name: "bindings", Kind: "Binding", Namespaced: true, Verbs: []string{"create"}},
+			},
+		},
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "deployments", Kind: "Deployment", Namespaced: true, Verbs: []string{"get", "list"}},
+			},
+		},
+		{
+			GroupVersion: "coordination.k8s.io/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "leases", Kind: "Lease", Namespaced: true, Verbs: []string{"get", "list"}},
+			},
+		},
+		{
+			GroupVersion: "example.com/v1alpha1",
+			APIResources: []metav1.APIResource{
+				{Name: "widgets", Kind: "Widget", Namespaced: false, Verbs: []string{"get", "list"}},
+			},
+		},
+	}
+
+	testItems := map[schema.GroupVersionResource][]unstructured.Unstructured{
+		{Group: "coordination.k8s.io", Version: "v1", Resource: "leases"}: {
+			newUnstructured("coordination.k8s.io/v1", "Lease", "default", "leader"),
+			newUnstructured("coordination.k8s.io/v1", "Lease", "production", "leader"),
+		},
+		{Group: "example.com", Version: "v1alpha1", Resource: "widgets"}: {
+			newUnstructured("example.com/v1alpha1", "Widget", "", "gadget"),
+		},
+	}
+
+	var listed []schema.GroupVersionResource
+	mockClient := &kube.MockClient{
+		GetPreferredResourcesFunc: func(ctx context.Context) ([]*metav1.APIResourceList, error) {
+			return testResourceLists, nil
+		},
+		ListResourcesFunc: func(ctx context.Context, gvr schema.GroupVersionResource, namespaced bool, namespace string, opts metav1.ListOptions) ([]unstructured.Unstructured, error) {
+			listed = append(listed, gvr)
+			return testItems[gvr], nil
+		},
+	}
+
+	collector := NewDynamicCollector(mockClient, KindFilter{})
+	resources, err := collector.Collect(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, resources, 3)
+
+	// Subresources, non-listable and skipped resources are never listed
+	assert.ElementsMatch(t, []schema.GroupVersionResource{
+		{Group: "coordination.k8s.io", Version: "v1", Resource: "leases"},
+		{Group: "example.com", Version: "v1alpha1", Resource: "widgets"},
+	}, listed)
+
+	for _, resource := range resources {
+		switch resource.Kind {
+		case "lease":
+			assert.Equal(t, "coordination.k8s.io", resource.Group)
+			assert.Equal(t, "v1", resource.Version)
+			assert.Equal(t, "leader", resource.Name)
+			assert.Contains(t, []string{"default", "production"}, resource.Metadata["namespace"])
+		case "widget":
+			assert.Equal(t, "example.com", resource.Group)
+			assert.Equal(t, "v1alpha1", resource.Version)
+			assert.Equal(t, "gadget", resource.Name)
+			assert.NotContains(t, resource.Metadata, "namespace")
+		default:
+			t.Errorf("Unexpected resource kind: %s", resource.Kind)
+		}
+	}
+}
+
+func TestDynamicCollector_Collect_DiscoveryError(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetPreferredResourcesFunc: func(ctx context.Context) ([]*metav1.APIResourceList, error) {
+			return nil, errors.New("failed to connect to cluster")
+		},
+	}
+
+	collector := NewDynamicCollector(mockClient, KindFilter{})
+	resources, err := collector.Collect(context.Background())
+
+	assert.Error(t, err)
+	assert.Nil(t, resources)
+	assert.Contains(t, err.Error(), "failed to connect to cluster")
+}
+
+func TestDynamicCollector_Collect_ListError(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetPreferredResourcesFunc: func(ctx context.Context) ([]*metav1.APIResourceList, error) {
+			return []*metav1.APIResourceList{
+				{
+					GroupVersion: "coordination.k8s.io/v1",
+					APIResources: []metav1.APIResource{
+						{Name: "leases", Kind: "Lease", Namespaced: true, Verbs: []string{"list"}},
+					},
+				},
+			}, nil
+		},
+		ListResourcesFunc: func(ctx context.Context, gvr schema.GroupVersionResource, namespaced bool, namespace string, opts metav1.ListOptions) ([]unstructured.Unstructured, error) {
+			return nil, errors.New("failed to list")
+		},
+	}
+
+	collector := NewDynamicCollector(mockClient, KindFilter{})
+	resources, err := collector.Collect(context.Background())
+
+	assert.Error(t, err)
+	assert.Nil(t, resources)
+	assert.Contains(t, err.Error(), "leases")
+}
+
+func newLeaseMockClient(listed *[]schema.GroupVersionResource) *kube.MockClient {
+	return &kube.MockClient{
+		GetPreferredResourcesFunc: func(ctx context.Context) ([]*metav1.APIResourceList, error) {
+			return []*metav1.APIResourceList{
+				{
+					GroupVersion: "coordination.k8s.io/v1",
+					APIResources: []metav1.APIResource{
+						{Name: "leases", Kind: "Lease", Namespaced: true, Verbs: []string{"list"}},
+					},
+				},
+				{
+					GroupVersion: "example.com/v1alpha1",
+					APIResources: []metav1.APIResource{
+						{Name: "widgets", Kind: "Widget", Namespaced: false, Verbs: []string{"list"}},
+					},
+				},
+			}, nil
+		},
+		ListResourcesFunc: func(ctx context.Context, gvr schema.GroupVersionResource, namespaced bool, namespace string, opts metav1.ListOptions) ([]unstructured.Unstructured, error) {
+			*listed = append(*listed, gvr)
+			return []unstructured.Unstructured{}, nil
+		},
+	}
+}
+
+func TestDynamicCollector_Collect_ExcludedKind(t *testing.T) {
+	var listed []schema.GroupVersionResource
+	mockClient := newLeaseMockClient(&listed)
+
+	collector := NewDynamicCollector(mockClient, KindFilter{Exclude: []string{"Lease"}})
+	_, err := collector.Collect(context.Background())
+
+	assert.NoError(t, err)
+	// Excluded kinds are never listed
+	assert.Equal(t, []schema.GroupVersionResource{
+		{Group: "example.com", Version: "v1alpha1", Resource: "widgets"},
+	}, listed)
+}
+
+func TestDynamicCollector_DiscoverKinds(t *testing.T) {
+	var listed []schema.GroupVersionResource
+	mockClient := newLeaseMockClient(&listed)
+
+	collector := NewDynamicCollector(mockClient, KindFilter{Exclude: []string{"lease"}})
+	kinds, err := collector.DiscoverKinds(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []KindInfo{
+		{Kind: "lease", Collector: "dynamic", Group: "coordination.k8s.io"},
+		{Kind: "widget", Collector: "dynamic", Group: "example.com"},
+	}, kinds)
+	assert.Empty(t, listed)
+}
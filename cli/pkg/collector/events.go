@@ -0,0 +1,251 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// EventsCollector collects events, folding repeated occurrences of the same
+// event into a single series
+type EventsCollector struct {
+	client kube.Client
+	// since limits collection to events last seen within the window, zero
+	// collects every event
+	since time.Duration
+	now   func() time.Time
+}
+
+func NewEventsCollector(client kube.Client, since time.Duration) *EventsCollector {
+	return &EventsCollector{client: client, since: since, now: time.Now}
+}
+
+func (c *EventsCollector) Name() string {
+	return "events"
+}
+
+func (c *EventsCollector) Kinds() []string {
+	return []string{"event"}
+}
+
+func (c *EventsCollector) Collect(ctx context.Context) ([]ClusterResource, error) {
+	namespaces, err := c.client.GetNamespaces(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get namespaces for event collection: %w", err), "namespace", "")
+	}
+
+	return collectEach(ctx, namespaces, func(ctx context.Context, namespace corev1.Namespace) ([]ClusterResource, error) {
+		return c.collectEvents(ctx, namespace.Name)
+	})
+}
+
+func (c *EventsCollector) collectEvents(ctx context.Context, namespace string) ([]ClusterResource, error) {
+	var resources []ClusterResource
+
+	coreEvents, err := c.client.GetEvents(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get events from namespace %s: %w", namespace, err), "event", namespace)
+	}
+
+	// events.k8s.io is a richer view of the same objects, older clusters
+	// don't serve it
+	var p partial
+	events, err := c.client.GetEventsV1(ctx, namespace, metav1.ListOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		if err := p.tolerate(fmt.Errorf("failed to get events.k8s.io events from namespace %s: %w", namespace, err), "event", namespace); err != nil {
+			return nil, err
+		}
+	}
+
+	byUID := make(map[types.UID]eventsv1.Event)
+	for _, event := range coreEvents {
+		byUID[event.UID] = fromCoreEvent(event)
+	}
+	for _, event := range events {
+		byUID[event.UID] = event
+	}
+
+	var cutoff time.Time
+	if c.since > 0 {
+		cutoff = c.now().Add(-c.since)
+	}
+
+	for _, series := range foldEvents(byUID) {
+		if series.lastSeen.Before(cutoff) {
+			continue
+		}
+
+		event := series.event
+		resources = append(resources, ClusterResource{
+			Kind:    "event",
+			Group:   "events.k8s.io",
+			Version: "v1",
+			Name:    event.Name,
+			Data:    event,
+			Metadata: map[string]string{
+				"namespace":               namespace,
+				"type":                    event.Type,
+				"reason":                  event.Reason,
+				"count":                   strconv.Itoa(int(series.count)),
+				"firstSeen":               series.firstSeen.UTC().Format(time.RFC3339),
+				"lastSeen":                series.lastSeen.UTC().Format(time.RFC3339),
+				"involvedObjectKind":      event.Regarding.Kind,
+				"involvedObjectNamespace": event.Regarding.Namespace,
+				"involvedObjectName":      event.Regarding.Name,
+				"involvedObjectUID":       string(event.Regarding.UID),
+			},
+		})
+	}
+
+	p.resources = resources
+	return p.result()
+}
+
+type eventSeries struct {
+	event     eventsv1.Event
+	count     int32
+	firstSeen time.Time
+	lastSeen  time.Time
+}
+
+// eventKey identifies repeated occurrences of the same event
+type eventKey struct {
+	regarding           corev1.ObjectReference
+	reason              string
+	eventType           string
+	note                string
+	reportingController string
+}
+
+// foldEvents merges events that only differ in when they happened, keeping
+// the latest occurrence with the series count and time span of all of them
+func foldEvents(events map[types.UID]eventsv1.Event) []eventSeries {
+	folded := make(map[eventKey]*eventSeries)
+	var keys []eventKey
+
+	for _, event := range events {
+		key := eventKey{
+			regarding:           event.Regarding,
+			reason:              event.Reason,
+			eventType:           event.Type,
+			note:                event.Note,
+			reportingController: event.ReportingController,
+		}
+		// The resource version changes with every series update
+		key.regarding.ResourceVersion = ""
+
+		count, firstSeen, lastSeen := eventOccurrences(event)
+
+		series, exists := folded[key]
+		if !exists {
+			folded[key] = &eventSeries{event: event, count: count, firstSeen: firstSeen, lastSeen: lastSeen}
+			keys = append(keys, key)
+			continue
+		}
+
+		series.count += count
+		if firstSeen.Before(series.firstSeen) {
+			series.firstSeen = firstSeen
+		}
+		if lastSeen.After(series.lastSeen) || (lastSeen.Equal(series.lastSeen) && event.Name > series.event.Name) {
+			series.lastSeen = lastSeen
+			series.event = event
+		}
+	}
+
+	result := make([]eventSeries, 0, len(keys))
+	for _, key := range keys {
+		series := folded[key]
+		series.event.Series = &eventsv1.EventSeries{
+			Count:            series.count,
+			LastObservedTime: metav1.NewMicroTime(series.lastSeen),
+		}
+		series.event.DeprecatedCount = series.count
+		series.event.DeprecatedFirstTimestamp = metav1.NewTime(series.firstSeen)
+		series.event.DeprecatedLastTimestamp = metav1.NewTime(series.lastSeen)
+		result = append(result, *series)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].lastSeen.Equal(result[j].lastSeen) {
+			return result[i].lastSeen.Before(result[j].lastSeen)
+		}
+		return result[i].event.Name < result[j].event.Name
+	})
+
+	return result
+}
+
+// eventOccurrences returns how often an event happened and when it was first
+// and last seen, falling back through the fields older reporters fill in
+func eventOccurrences(event eventsv1.Event) (int32, time.Time, time.Time) {
+	count := int32(1)
+	if event.Series != nil && event.Series.Count > 0 {
+		count = event.Series.Count
+	} else if event.DeprecatedCount > 0 {
+		count = event.DeprecatedCount
+	}
+
+	firstSeen := event.CreationTimestamp.Time
+	switch {
+	case !event.DeprecatedFirstTimestamp.IsZero():
+		firstSeen = event.DeprecatedFirstTimestamp.Time
+	case !event.EventTime.IsZero():
+		firstSeen = event.EventTime.Time
+	}
+
+	lastSeen := firstSeen
+	switch {
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		lastSeen = event.Series.LastObservedTime.Time
+	case !event.DeprecatedLastTimestamp.IsZero():
+		lastSeen = event.DeprecatedLastTimestamp.Time
+	}
+
+	return count, firstSeen, lastSeen
+}
+
+// fromCoreEvent converts a core/v1 event the same way the API server
+// converts it for events.k8s.io/v1
+func fromCoreEvent(event corev1.Event) eventsv1.Event {
+	converted := eventsv1.Event{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "events.k8s.io/v1",
+			Kind:       "Event",
+		},
+		ObjectMeta:               event.ObjectMeta,
+		EventTime:                event.EventTime,
+		ReportingController:      event.ReportingController,
+		ReportingInstance:        event.ReportingInstance,
+		Action:                   event.Action,
+		Reason:                   event.Reason,
+		Regarding:                event.InvolvedObject,
+		Related:                  event.Related,
+		Note:                     event.Message,
+		Type:                     event.Type,
+		DeprecatedSource:         event.Source,
+		DeprecatedFirstTimestamp: event.FirstTimestamp,
+		DeprecatedLastTimestamp:  event.LastTimestamp,
+		DeprecatedCount:          event.Count,
+	}
+	if converted.ReportingController == "" {
+		converted.ReportingController = event.Source.Component
+	}
+	if event.Series != nil {
+		converted.Series = &eventsv1.EventSeries{
+			Count:            event.Series.Count,
+			LastObservedTime: event.Series.LastObservedTime,
+		}
+	}
+
+	return converted
+}
@@ -0,0 +1,192 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var testEventsNow = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func newCoreEvent(name string, uid string, reason string, count int32, last time.Time) corev1.Event {
+	return corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(uid)},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			Namespace: "default",
+			Name:      "web-123",
+			UID:       "pod-uid",
+		},
+		Reason:         reason,
+		Message:        "Back-off restarting failed container",
+		Type:           corev1.EventTypeWarning,
+		Count:          count,
+		FirstTimestamp: metav1.NewTime(last.Add(-time.Hour)),
+		LastTimestamp:  metav1.NewTime(last),
+	}
+}
+
+func TestEventsCollector_Name(t *testing.T) {
+	mockClient := &kube.MockClient{}
+	collector := NewEventsCollector(mockClient, 0)
+
+	assert.Equal(t, "events", collector.Name())
+}
+
+func TestEventsCollector_Collect_FoldsSeries(t *testing.T) {
+	coreEvents := []corev1.Event{
+		newCoreEvent("web-123.1", "uid-1", "BackOff", 5, testEventsNow.Add(-30*time.Minute)),
+		newCoreEvent("web-123.2", "uid-2", "BackOff", 3, testEventsNow.Add(-10*time.Minute)),
+		newCoreEvent("web-123.3", "uid-3", "Pulled", 1, testEventsNow.Add(-20*time.Minute)),
+	}
+
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		GetEventsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Event, error) {
+			return coreEvents, nil
+		},
+		GetEventsV1Func: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]eventsv1.Event, error) {
+			return []eventsv1.Event{}, nil
+		},
+	}
+
+	collector := NewEventsCollector(mockClient, 0)
+	collector.now = func() time.Time { return testEventsNow }
+	resources, err := collector.Collect(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, resources, 2)
+
+	backOff := resources[1]
+	assert.Equal(t, "event", backOff.Kind)
+	assert.Equal(t, "web-123.2", backOff.Name)
+	assert.Equal(t, "BackOff", backOff.Metadata["reason"])
+	assert.Equal(t, "8", backOff.Metadata["count"])
+	assert.Equal(t, "Pod", backOff.Metadata["involvedObjectKind"])
+	assert.Equal(t, "web-123", backOff.Metadata["involvedObjectName"])
+	assert.Equal(t, "pod-uid", backOff.Metadata["involvedObjectUID"])
+	assert.Equal(t, testEventsNow.Add(-90*time.Minute).Format(time.RFC3339), backOff.Metadata["firstSeen"])
+	assert.Equal(t, testEventsNow.Add(-10*time.Minute).Format(time.RFC3339), backOff.Metadata["lastSeen"])
+
+	event, ok := backOff.Data.(eventsv1.Event)
+	assert.True(t, ok)
+	assert.Equal(t, int32(8), event.Series.Count)
+	assert.Equal(t, "web-123", event.Regarding.Name)
+}
+
+func TestEventsCollector_Collect_MergesAPIVersions(t *testing.T) {
+	last := testEventsNow.Add(-time.Minute)
+	coreEvents := []corev1.Event{
+		newCoreEvent("web-123.1", "uid-1", "BackOff", 2, last),
+	}
+	events := []eventsv1.Event{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-123.1", Namespace: "default", UID: "uid-1"},
+			Regarding:  coreEvents[0].InvolvedObject,
+			Reason:     "BackOff",
+			Note:       "Back-off restarting failed container",
+			Type:       corev1.EventTypeWarning,
+			Series: &eventsv1.EventSeries{
+				Count:            4,
+				LastObservedTime: metav1.NewMicroTime(last),
+			},
+		},
+	}
+
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		GetEventsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Event, error) {
+			return coreEvents, nil
+		},
+		GetEventsV1Func: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]eventsv1.Event, error) {
+			return events, nil
+		},
+	}
+
+	collector := NewEventsCollector(mockClient, 0)
+	resources, err := collector.Collect(context.Background())
+
+	// The same event is served by both APIs and must only be counted once
+	assert.NoError(t, err)
+	assert.Len(t, resources, 1)
+	assert.Equal(t, "4", resources[0].Metadata["count"])
+}
+
+func TestEventsCollector_Collect_Since(t *testing.T) {
+	coreEvents := []corev1.Event{
+		newCoreEvent("old", "uid-1", "Scheduled", 1, testEventsNow.Add(-3*time.Hour)),
+		newCoreEvent("recent", "uid-2", "Pulled", 1, testEventsNow.Add(-5*time.Minute)),
+	}
+
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		GetEventsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Event, error) {
+			return coreEvents, nil
+		},
+		GetEventsV1Func: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]eventsv1.Event, error) {
+			return []eventsv1.Event{}, nil
+		},
+	}
+
+	collector := NewEventsCollector(mockClient, time.Hour)
+	collector.now = func() time.Time { return testEventsNow }
+	resources, err := collector.Collect(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, resources, 1)
+	assert.Equal(t, "recent", resources[0].Name)
+}
+
+func TestEventsCollector_Collect_EventsV1NotServed(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		GetEventsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Event, error) {
+			return []corev1.Event{newCoreEvent("web-123.1", "uid-1", "BackOff", 1, testEventsNow)}, nil
+		},
+		GetEventsV1Func: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]eventsv1.Event, error) {
+			return nil, apierrors.NewNotFound(schema.GroupResource{Group: "events.k8s.io", Resource: "events"}, "")
+		},
+	}
+
+	collector := NewEventsCollector(mockClient, 0)
+	resources, err := collector.Collect(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, resources, 1)
+}
+
+func TestEventsCollector_Collect_EventError(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		GetEventsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Event, error) {
+			return nil, errors.New("failed to get events")
+		},
+	}
+
+	collector := NewEventsCollector(mockClient, 0)
+	resources, err := collector.Collect(context.Background())
+
+	assert.Error(t, err)
+	assert.Nil(t, resources)
+	assert.Contains(t, err.Error(), "failed to get events")
+}
@@ -0,0 +1,163 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+)
+
+// Failure records a kind that could not be collected from a namespace, or
+// from the whole cluster when Namespace is empty. Without a Kind the whole
+// collector failed
+type Failure struct {
+	Collector string `json:"collector,omitempty"`
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	// Name is only set when a single object failed
+	Name string `json:"name,omitempty"`
+	// Reason is the reason reported by the API server such as Forbidden,
+	// Timeout when the request ran out of time or ConnectionLost when the
+	// connection kept breaking
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+func (f *Failure) Error() string {
+	return f.Message
+}
+
+// Failures is returned by Collect along with the resources that were
+// collected when only some kinds or namespaces failed
+type Failures []*Failure
+
+func (f Failures) Error() string {
+	messages := make([]string, len(f))
+	for i, failure := range f {
+		messages[i] = failure.Error()
+	}
+	return fmt.Sprintf("%d collection failures: %s", len(f), strings.Join(messages, "; "))
+}
+
+// Summary counts the failures per kind and reason, the failures themselves
+// end up in errors.json
+func (f Failures) Summary() string {
+	if len(f) == 0 {
+		return ""
+	}
+
+	counts := make(map[string]int)
+	for _, failure := range f {
+		// Failures without a kind concern the whole collector
+		part := failure.Kind
+		if part == "" {
+			part = failure.Collector
+		}
+		counts[part+" ("+failure.Reason+")"]++
+	}
+
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "Incomplete: %d parts of the cluster could not be collected, see errors.json\n", len(f))
+	for _, key := range keys {
+		fmt.Fprintf(&summary, "  %-40s %d\n", key, counts[key])
+	}
+
+	return summary.String()
+}
+
+// tolerate turns an error the rest of the snapshot can do without into a
+// failure of a kind in a namespace. Errors that do not come from the API
+// server, such as an unreachable cluster, are returned as they are
+func tolerate(err error, kind string, namespace string) error {
+	return tolerateObject(err, kind, namespace, "")
+}
+
+// tolerateObject is tolerate for a single object
+func tolerateObject(err error, kind string, namespace string, name string) error {
+	if err == nil {
+		return nil
+	}
+
+	reason, tolerable := failureReason(err)
+	if !tolerable {
+		return err
+	}
+
+	return Failures{{Kind: kind, Namespace: namespace, Name: name, Reason: reason, Message: err.Error()}}
+}
+
+// failureReason returns why a request failed and whether the failure only
+// affects that request
+func failureReason(err error) (string, bool) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "Timeout", true
+	}
+
+	// A request that timed out on the client or lost its connection after
+	// its retries says nothing about the requests around it, a refused
+	// connection does
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return "Timeout", true
+	}
+	if utilnet.IsConnectionReset(err) || utilnet.IsProbableEOF(err) || utilnet.IsHTTP2ConnectionLost(err) {
+		return "ConnectionLost", true
+	}
+
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) {
+		return "Unknown", false
+	}
+	if reason := status.Status().Reason; reason != "" {
+		return string(reason), true
+	}
+
+	return "Unknown", true
+}
+
+// partial gathers the parts of a collection, some of which may have failed
+type partial struct {
+	resources []ClusterResource
+	failures  Failures
+}
+
+// add keeps the resources and failures of a part, any other error is
+// returned
+func (p *partial) add(resources []ClusterResource, err error) error {
+	var failures Failures
+	if err != nil && !errors.As(err, &failures) {
+		return err
+	}
+
+	p.resources = append(p.resources, resources...)
+	p.failures = append(p.failures, failures...)
+
+	return nil
+}
+
+// tolerate keeps the failure of a part that the rest of the collection can
+// do without, any other error is returned
+func (p *partial) tolerate(err error, kind string, namespace string) error {
+	return p.add(nil, tolerate(err, kind, namespace))
+}
+
+// result returns the resources, with the failures as error when a part
+// failed
+func (p *partial) result() ([]ClusterResource, error) {
+	if len(p.failures) > 0 {
+		return p.resources, p.failures
+	}
+
+	return p.resources, nil
+}
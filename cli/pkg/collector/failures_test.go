@@ -0,0 +1,90 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestTolerate(t *testing.T) {
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "", errors.New("no access"))
+
+	tests := []struct {
+		name   string
+		err    error
+		reason string
+	}{
+		{name: "forbidden", err: fmt.Errorf("failed to get secrets: %w", forbidden), reason: "Forbidden"},
+		{name: "not found", err: apierrors.NewNotFound(schema.GroupResource{Resource: "widgets"}, ""), reason: "NotFound"},
+		{name: "deadline", err: fmt.Errorf("failed to get pods: %w", context.DeadlineExceeded), reason: "Timeout"},
+		{name: "client timeout", err: &url.Error{Op: "Get", URL: "https://10.0.0.1/api/v1/pods", Err: timeoutError{}}, reason: "Timeout"},
+		{name: "connection reset", err: errors.New("read tcp 10.0.0.2:51234->10.0.0.1:443: read: connection reset by peer"), reason: "ConnectionLost"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var failures Failures
+			assert.ErrorAs(t, tolerate(tt.err, "secret", "team-a"), &failures)
+			assert.Len(t, failures, 1)
+			assert.Equal(t, "secret", failures[0].Kind)
+			assert.Equal(t, "team-a", failures[0].Namespace)
+			assert.Equal(t, tt.reason, failures[0].Reason)
+			assert.Equal(t, tt.err.Error(), failures[0].Message)
+		})
+	}
+}
+
+// timeoutError is a net.Error of a request the client gave up on
+type timeoutError struct{}
+
+func (timeoutError) Error() string {
+	return "net/http: request canceled (Client.Timeout exceeded while awaiting headers)"
+}
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestTolerate_OtherErrors(t *testing.T) {
+	// An unreachable cluster fails everything, it is not a partial failure
+	err := errors.New("connection refused")
+	assert.Same(t, err, tolerate(err, "pod", "team-a"))
+
+	assert.NoError(t, tolerate(nil, "pod", "team-a"))
+}
+
+func TestPartial(t *testing.T) {
+	var p partial
+
+	assert.NoError(t, p.add([]ClusterResource{{Kind: "pod", Name: "web"}}, nil))
+	assert.NoError(t, p.add(nil, Failures{{Kind: "secret", Reason: "Forbidden"}}))
+	assert.NoError(t, p.add([]ClusterResource{{Kind: "pod", Name: "api"}}, nil))
+
+	failure := errors.New("connection refused")
+	assert.ErrorIs(t, p.add(nil, failure), failure)
+
+	resources, err := p.result()
+	var failures Failures
+	assert.ErrorAs(t, err, &failures)
+	assert.Len(t, failures, 1)
+	assert.Len(t, resources, 2)
+}
+
+func TestFailures_Summary(t *testing.T) {
+	failures := Failures{
+		{Kind: "secret", Namespace: "team-a", Reason: "Forbidden"},
+		{Kind: "secret", Namespace: "team-b", Reason: "Forbidden"},
+		{Kind: "pod", Namespace: "team-a", Reason: "Timeout"},
+	}
+
+	summary := failures.Summary()
+
+	assert.Contains(t, summary, "3 parts of the cluster could not be collected")
+	assert.Regexp(t, `pod \(Timeout\)\s+1`, summary)
+	assert.Regexp(t, `secret \(Forbidden\)\s+2`, summary)
+	assert.Empty(t, Failures{}.Summary())
+}
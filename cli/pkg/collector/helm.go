@@ -0,0 +1,218 @@
+package collector
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	"github.com/3nd3r1/kubin/cli/pkg/log"
+	"github.com/3nd3r1/kubin/cli/pkg/redact"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// helmReleaseSelector matches the secrets the Helm v3 secret storage driver
+// keeps one release revision in
+const helmReleaseSelector = "owner=helm"
+
+// HelmRelease is the current revision of a Helm release together with the
+// history of earlier revisions
+type HelmRelease struct {
+	Name          string `json:"name"`
+	Namespace     string `json:"namespace"`
+	Chart         string `json:"chart"`
+	ChartVersion  string `json:"chartVersion"`
+	AppVersion    string `json:"appVersion,omitempty"`
+	Revision      int    `json:"revision"`
+	Status        string `json:"status"`
+	Description   string `json:"description,omitempty"`
+	FirstDeployed string `json:"firstDeployed,omitempty"`
+	LastDeployed  string `json:"lastDeployed,omitempty"`
+	// Values are the user supplied values, chart defaults are not included
+	Values   map[string]interface{} `json:"values"`
+	Manifest string                 `json:"manifest"`
+	History  []HelmRevision         `json:"history"`
+}
+
+type HelmRevision struct {
+	Revision     int    `json:"revision"`
+	Status       string `json:"status"`
+	Chart        string `json:"chart"`
+	ChartVersion string `json:"chartVersion"`
+	AppVersion   string `json:"appVersion,omitempty"`
+	Updated      string `json:"updated,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// helmReleaseRecord is the subset of the Helm release object stored in a
+// release secret that is needed here
+type helmReleaseRecord struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Version   int    `json:"version"`
+	Info      struct {
+		FirstDeployed string `json:"first_deployed"`
+		LastDeployed  string `json:"last_deployed"`
+		Description   string `json:"description"`
+		Status        string `json:"status"`
+	} `json:"info"`
+	Chart struct {
+		Metadata struct {
+			Name       string `json:"name"`
+			Version    string `json:"version"`
+			AppVersion string `json:"appVersion"`
+		} `json:"metadata"`
+	} `json:"chart"`
+	Config   map[string]interface{} `json:"config"`
+	Manifest string                 `json:"manifest"`
+}
+
+// HelmCollector decodes Helm v3 release secrets into readable releases
+type HelmCollector struct {
+	client kube.Client
+}
+
+func NewHelmCollector(client kube.Client) *HelmCollector {
+	return &HelmCollector{client: client}
+}
+
+func (c *HelmCollector) Name() string {
+	return "helm"
+}
+
+func (c *HelmCollector) Kinds() []string {
+	return []string{"helmrelease"}
+}
+
+func (c *HelmCollector) Collect(ctx context.Context) ([]ClusterResource, error) {
+	namespaces, err := c.client.GetNamespaces(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get namespaces for helm collection: %w", err), "namespace", "")
+	}
+
+	return collectEach(ctx, namespaces, c.collectNamespace)
+}
+
+func (c *HelmCollector) collectNamespace(ctx context.Context, namespace corev1.Namespace) ([]ClusterResource, error) {
+	var resources []ClusterResource
+
+	secrets, err := c.client.GetSecrets(ctx, namespace.Name, metav1.ListOptions{LabelSelector: helmReleaseSelector})
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get helm release secrets from namespace %s: %w", namespace.Name, err), "helmrelease", namespace.Name)
+	}
+
+	releases := make(map[string][]helmReleaseRecord)
+	for _, secret := range secrets {
+		record, err := decodeHelmRelease(secret)
+		if err != nil {
+			log.WithError(err).Warnw("Failed to decode helm release", "namespace", secret.Namespace, "secret", secret.Name)
+			continue
+		}
+		releases[record.Name] = append(releases[record.Name], record)
+	}
+
+	names := make([]string, 0, len(releases))
+	for name := range releases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		release := helmRelease(releases[name])
+		resources = append(resources, ClusterResource{
+			Kind: "helmrelease",
+			Name: release.Name,
+			Data: release,
+			Metadata: map[string]string{
+				"namespace":    namespace.Name,
+				"chart":        release.Chart,
+				"chartVersion": release.ChartVersion,
+				"appVersion":   release.AppVersion,
+				"revision":     strconv.Itoa(release.Revision),
+				"status":       release.Status,
+			},
+		})
+	}
+
+	return resources, nil
+}
+
+// helmRelease builds a release from all of its revisions, the latest
+// revision provides the values and manifest
+func helmRelease(records []helmReleaseRecord) HelmRelease {
+	sort.Slice(records, func(i, j int) bool { return records[i].Version < records[j].Version })
+	latest := records[len(records)-1]
+
+	release := HelmRelease{
+		Name:          latest.Name,
+		Namespace:     latest.Namespace,
+		Chart:         latest.Chart.Metadata.Name,
+		ChartVersion:  latest.Chart.Metadata.Version,
+		AppVersion:    latest.Chart.Metadata.AppVersion,
+		Revision:      latest.Version,
+		Status:        latest.Info.Status,
+		Description:   latest.Info.Description,
+		FirstDeployed: records[0].Info.FirstDeployed,
+		LastDeployed:  latest.Info.LastDeployed,
+		Values:        redact.Values(latest.Config),
+		Manifest:      redact.Manifest(latest.Manifest),
+	}
+
+	for _, record := range records {
+		release.History = append(release.History, HelmRevision{
+			Revision:     record.Version,
+			Status:       record.Info.Status,
+			Chart:        record.Chart.Metadata.Name,
+			ChartVersion: record.Chart.Metadata.Version,
+			AppVersion:   record.Chart.Metadata.AppVersion,
+			Updated:      record.Info.LastDeployed,
+			Description:  record.Info.Description,
+		})
+	}
+
+	return release
+}
+
+// decodeHelmRelease decodes the release stored in a secret, which Helm
+// encodes as base64 of the gzipped release json
+func decodeHelmRelease(secret corev1.Secret) (helmReleaseRecord, error) {
+	var record helmReleaseRecord
+
+	encoded, exists := secret.Data["release"]
+	if !exists {
+		return record, fmt.Errorf("secret has no release data")
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(string(encoded))
+	if err != nil {
+		return record, fmt.Errorf("failed to decode release: %w", err)
+	}
+
+	// Releases are gzipped unless they were written by very old Helm 3
+	// versions
+	if bytes.HasPrefix(decoded, []byte{0x1f, 0x8b}) {
+		reader, err := gzip.NewReader(bytes.NewReader(decoded))
+		if err != nil {
+			return record, fmt.Errorf("failed to decompress release: %w", err)
+		}
+		defer reader.Close()
+
+		decoded, err = io.ReadAll(reader)
+		if err != nil {
+			return record, fmt.Errorf("failed to decompress release: %w", err)
+		}
+	}
+
+	if err := json.Unmarshal(decoded, &record); err != nil {
+		return record, fmt.Errorf("failed to unmarshal release: %w", err)
+	}
+
+	return record, nil
+}
@@ -0,0 +1,130 @@
+package collector
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	"github.com/3nd3r1/kubin/cli/pkg/redact"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newHelmReleaseSecret(t *testing.T, name string, revision int, status string, chartVersion string) corev1.Secret {
+	release := map[string]interface{}{
+		"name":      name,
+		"namespace": "default",
+		"version":   revision,
+		"info": map[string]interface{}{
+			"status":        status,
+			"last_deployed": fmt.Sprintf("2025-01-0%dT00:00:00Z", revision),
+		},
+		"chart": map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "nginx", "version": chartVersion, "appVersion": "1.27.0"},
+		},
+		"config": map[string]interface{}{
+			"replicaCount": 2,
+			"auth":         map[string]interface{}{"password": "hunter2"},
+		},
+		"manifest": "---\n# Source: nginx/templates/secret.yaml\napiVersion: v1\nkind: Secret\nmetadata:\n  name: web\ndata:\n  password: aHVudGVyMg==\n",
+	}
+
+	raw, err := json.Marshal(release)
+	assert.NoError(t, err)
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, err = writer.Write(raw)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	return corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("sh.helm.release.v1.%s.v%d", name, revision),
+			Namespace: "default",
+			Labels:    map[string]string{"owner": "helm", "name": name},
+		},
+		Type: "helm.sh/release.v1",
+		Data: map[string][]byte{
+			"release": []byte(base64.StdEncoding.EncodeToString(compressed.Bytes())),
+		},
+	}
+}
+
+func TestHelmCollector_Name(t *testing.T) {
+	mockClient := &kube.MockClient{}
+	collector := NewHelmCollector(mockClient)
+
+	assert.Equal(t, "helm", collector.Name())
+}
+
+func TestHelmCollector_Collect_Success(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		GetSecretsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Secret, error) {
+			assert.Equal(t, "owner=helm", opts.LabelSelector)
+			return []corev1.Secret{
+				newHelmReleaseSecret(t, "web", 2, "deployed", "15.1.0"),
+				newHelmReleaseSecret(t, "web", 1, "superseded", "15.0.0"),
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "sh.helm.release.v1.broken.v1", Namespace: "default"},
+					Data:       map[string][]byte{"release": []byte("not base64")},
+				},
+			}, nil
+		},
+	}
+
+	collector := NewHelmCollector(mockClient)
+	resources, err := collector.Collect(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, resources, 1)
+
+	resource := resources[0]
+	assert.Equal(t, "helmrelease", resource.Kind)
+	assert.Equal(t, "web", resource.Name)
+	assert.Equal(t, "default", resource.Metadata["namespace"])
+	assert.Equal(t, "15.1.0", resource.Metadata["chartVersion"])
+	assert.Equal(t, "2", resource.Metadata["revision"])
+	assert.Equal(t, "deployed", resource.Metadata["status"])
+
+	release := resource.Data.(HelmRelease)
+	assert.Equal(t, "nginx", release.Chart)
+	assert.Equal(t, "1.27.0", release.AppVersion)
+	assert.Equal(t, float64(2), release.Values["replicaCount"])
+	assert.Equal(t, map[string]interface{}{"password": redact.Placeholder}, release.Values["auth"])
+	assert.NotContains(t, release.Manifest, "aHVudGVyMg==")
+	assert.Contains(t, release.Manifest, "# Source: nginx/templates/secret.yaml")
+
+	assert.Len(t, release.History, 2)
+	assert.Equal(t, 1, release.History[0].Revision)
+	assert.Equal(t, "superseded", release.History[0].Status)
+	assert.Equal(t, "15.0.0", release.History[0].ChartVersion)
+}
+
+func TestHelmCollector_Collect_SecretError(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		GetSecretsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Secret, error) {
+			return nil, errors.New("forbidden")
+		},
+	}
+
+	collector := NewHelmCollector(mockClient)
+	resources, err := collector.Collect(context.Background())
+
+	assert.Error(t, err)
+	assert.Nil(t, resources)
+	assert.Contains(t, err.Error(), "failed to get helm release secrets")
+}
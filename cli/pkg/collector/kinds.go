@@ -0,0 +1,115 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// KindFilter selects which resources are captured. Entries are case
+// insensitive glob patterns matching either a resource kind such as lease or
+// the name of the collector producing it such as events
+type KindFilter struct {
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// KindInfo describes a kind of resource that can be captured
+type KindInfo struct {
+	Kind      string
+	Collector string
+	// Group is only known for kinds found through discovery
+	Group string
+}
+
+// kindDiscoverer is implemented by collectors whose kinds depend on the
+// cluster
+type kindDiscoverer interface {
+	DiscoverKinds(ctx context.Context) ([]KindInfo, error)
+}
+
+// Validate checks that every pattern is a valid glob
+func (f KindFilter) Validate() error {
+	for _, pattern := range append(append([]string{}, f.Include...), f.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid kind pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// IsEmpty reports whether the filter captures everything
+func (f KindFilter) IsEmpty() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0
+}
+
+// Matches reports whether resources of a kind produced by a collector are
+// captured, an exclude wins over an include
+func (f KindFilter) Matches(collector string, kind string) bool {
+	if matchesAny(f.Exclude, collector, kind) {
+		return false
+	}
+
+	return len(f.Include) == 0 || matchesAny(f.Include, collector, kind)
+}
+
+// Runs reports whether a collector has anything to capture. Collectors
+// without a fixed list of kinds filter the kinds they discover themselves
+func (f KindFilter) Runs(c Collector) bool {
+	if matchesAny(f.Exclude, c.Name(), "") {
+		return false
+	}
+
+	kinds := c.Kinds()
+	if kinds == nil {
+		return true
+	}
+
+	for _, kind := range kinds {
+		if f.Matches(c.Name(), kind) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesAny(patterns []string, collector string, kind string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		if matched, _ := path.Match(pattern, collector); matched {
+			return true
+		}
+		if kind == "" {
+			continue
+		}
+		if matched, _ := path.Match(pattern, strings.ToLower(kind)); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ListKinds returns every kind the collectors can capture on the cluster
+func ListKinds(ctx context.Context, collectors []Collector) ([]KindInfo, error) {
+	var kinds []KindInfo
+
+	for _, c := range collectors {
+		if discoverer, ok := c.(kindDiscoverer); ok {
+			discovered, err := discoverer.DiscoverKinds(ctx)
+			if err != nil {
+				return nil, err
+			}
+			kinds = append(kinds, discovered...)
+			continue
+		}
+
+		for _, kind := range c.Kinds() {
+			kinds = append(kinds, KindInfo{Kind: kind, Collector: c.Name()})
+		}
+	}
+
+	return kinds, nil
+}
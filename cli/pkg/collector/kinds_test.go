@@ -0,0 +1,78 @@
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestKindFilter_Matches(t *testing.T) {
+	tests := []struct {
+		name      string
+		filter    KindFilter
+		collector string
+		kind      string
+		want      bool
+	}{
+		{name: "empty filter", filter: KindFilter{}, collector: "core", kind: "pod", want: true},
+		{name: "included kind", filter: KindFilter{Include: []string{"pod"}}, collector: "core", kind: "pod", want: true},
+		{name: "not included", filter: KindFilter{Include: []string{"pod"}}, collector: "core", kind: "namespace", want: false},
+		{name: "included collector", filter: KindFilter{Include: []string{"workloads"}}, collector: "workloads", kind: "job", want: true},
+		{name: "case insensitive", filter: KindFilter{Include: []string{"Deployment"}}, collector: "workloads", kind: "deployment", want: true},
+		{name: "glob", filter: KindFilter{Exclude: []string{"*binding"}}, collector: "rbac", kind: "clusterrolebinding", want: false},
+		{name: "excluded collector", filter: KindFilter{Exclude: []string{"events"}}, collector: "events", kind: "event", want: false},
+		{name: "exclude wins", filter: KindFilter{Include: []string{"workloads"}, Exclude: []string{"replicaset"}}, collector: "workloads", kind: "replicaset", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Matches(tt.collector, tt.kind))
+		})
+	}
+}
+
+func TestKindFilter_Runs(t *testing.T) {
+	events := NewEventsCollector(&kube.MockClient{}, 0)
+	dynamic := NewDynamicCollector(&kube.MockClient{}, KindFilter{})
+
+	assert.True(t, KindFilter{}.Runs(events))
+	assert.False(t, KindFilter{Exclude: []string{"event"}}.Runs(events))
+	assert.False(t, KindFilter{Include: []string{"pod"}}.Runs(events))
+	assert.True(t, KindFilter{Include: []string{"pod"}}.Runs(dynamic))
+	assert.False(t, KindFilter{Exclude: []string{"dynamic"}}.Runs(dynamic))
+}
+
+func TestKindFilter_Validate(t *testing.T) {
+	assert.NoError(t, KindFilter{Include: []string{"pod", "*binding"}}.Validate())
+	assert.Error(t, KindFilter{Exclude: []string{"[lease"}}.Validate())
+}
+
+func TestListKinds(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetPreferredResourcesFunc: func(ctx context.Context) ([]*metav1.APIResourceList, error) {
+			return []*metav1.APIResourceList{
+				{
+					GroupVersion: "coordination.k8s.io/v1",
+					APIResources: []metav1.APIResource{
+						{Name: "leases", Kind: "Lease", Namespaced: true, Verbs: []string{"list"}},
+					},
+				},
+			}, nil
+		},
+	}
+
+	kinds, err := ListKinds(context.Background(), []Collector{
+		NewCoreCollector(mockClient, KindFilter{}),
+		NewDynamicCollector(mockClient, KindFilter{}),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []KindInfo{
+		{Kind: "namespace", Collector: "core"},
+		{Kind: "pod", Collector: "core"},
+		{Kind: "lease", Collector: "dynamic", Group: "coordination.k8s.io"},
+	}, kinds)
+}
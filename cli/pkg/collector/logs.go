@@ -0,0 +1,146 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LogOptions limits how much of each container log is collected, zero values
+// mean no limit
+type LogOptions struct {
+	TailLines  int64
+	Since      time.Duration
+	LimitBytes int64
+}
+
+// LogsCollector collects the logs of every container and init container,
+// including the previous instance of restarted containers
+type LogsCollector struct {
+	client kube.Client
+	opts   LogOptions
+}
+
+func NewLogsCollector(client kube.Client, opts LogOptions) *LogsCollector {
+	return &LogsCollector{client: client, opts: opts}
+}
+
+func (c *LogsCollector) Name() string {
+	return "logs"
+}
+
+func (c *LogsCollector) Kinds() []string {
+	return []string{"log"}
+}
+
+func (c *LogsCollector) Collect(ctx context.Context) ([]ClusterResource, error) {
+	namespaces, err := c.client.GetNamespaces(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get namespaces for log collection: %w", err), "namespace", "")
+	}
+
+	namespacePods, err := forEach(ctx, namespaces, func(ctx context.Context, namespace corev1.Namespace) ([]corev1.Pod, error) {
+		pods, err := c.client.GetPods(ctx, namespace.Name, metav1.ListOptions{})
+		if err != nil {
+			return nil, tolerate(fmt.Errorf("failed to get pods from namespace %s: %w", namespace.Name, err), "log", namespace.Name)
+		}
+		return pods, nil
+	})
+
+	var p partial
+	if err := p.add(nil, err); err != nil {
+		return nil, err
+	}
+
+	var pods []corev1.Pod
+	for _, namespaced := range namespacePods {
+		pods = append(pods, namespaced...)
+	}
+
+	if err := p.add(collectEach(ctx, pods, c.collectPodLogs)); err != nil {
+		return nil, err
+	}
+
+	return p.result()
+}
+
+func (c *LogsCollector) collectPodLogs(ctx context.Context, pod corev1.Pod) ([]ClusterResource, error) {
+	var p partial
+
+	collect := func(container string, previous bool) error {
+		log, err := c.collectContainerLog(ctx, pod, container, previous)
+		if err != nil {
+			return p.add(nil, err)
+		}
+		return p.add([]ClusterResource{log}, nil)
+	}
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		// Containers that never started have no log to fetch
+		if status.State.Running != nil || status.State.Terminated != nil {
+			if err := collect(status.Name, false); err != nil {
+				return nil, err
+			}
+		}
+
+		if status.RestartCount > 0 && status.LastTerminationState.Terminated != nil {
+			if err := collect(status.Name, true); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return p.result()
+}
+
+func (c *LogsCollector) collectContainerLog(ctx context.Context, pod corev1.Pod, container string, previous bool) (ClusterResource, error) {
+	opts := corev1.PodLogOptions{
+		Container:  container,
+		Previous:   previous,
+		Timestamps: true,
+	}
+	if c.opts.TailLines > 0 {
+		opts.TailLines = &c.opts.TailLines
+	}
+	if c.opts.Since > 0 {
+		sinceSeconds := int64(c.opts.Since.Seconds())
+		opts.SinceSeconds = &sinceSeconds
+	}
+	if c.opts.LimitBytes > 0 {
+		opts.LimitBytes = &c.opts.LimitBytes
+	}
+
+	logs, err := c.client.GetPodLogs(ctx, pod.Namespace, pod.Name, opts)
+	if err != nil {
+		err = fmt.Errorf("failed to get logs of container %s in pod %s/%s: %w", container, pod.Namespace, pod.Name, err)
+		return ClusterResource{}, tolerateObject(err, "log", pod.Namespace, logFileName(pod.Name, container, previous))
+	}
+
+	return ClusterResource{
+		Kind: "log",
+		Name: logFileName(pod.Name, container, previous),
+		Data: logs,
+		Metadata: map[string]string{
+			"namespace": pod.Namespace,
+			"pod":       pod.Name,
+			"container": container,
+			"previous":  strconv.FormatBool(previous),
+			"truncated": strconv.FormatBool(c.opts.LimitBytes > 0 && int64(len(logs)) >= c.opts.LimitBytes),
+		},
+	}, nil
+}
+
+// logFileName returns the stable path of a container log relative to its
+// namespace: <pod>/<container>.log or <pod>/<container>.previous.log
+func logFileName(pod string, container string, previous bool) string {
+	if previous {
+		return fmt.Sprintf("%s/%s.previous.log", pod, container)
+	}
+	return fmt.Sprintf("%s/%s.log", pod, container)
+}
@@ -0,0 +1,151 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLogsCollector_Name(t *testing.T) {
+	mockClient := &kube.MockClient{}
+	collector := NewLogsCollector(mockClient, LogOptions{})
+
+	assert.Equal(t, "logs", collector.Name())
+}
+
+func TestLogsCollector_Collect_Success(t *testing.T) {
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	terminated := corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}}
+	waiting := corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}}
+
+	pods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-123", Namespace: "default"},
+			Status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{
+					{Name: "migrate", State: terminated},
+				},
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "nginx", State: running, RestartCount: 2, LastTerminationState: terminated},
+					{Name: "sidecar", State: waiting},
+				},
+			},
+		},
+	}
+
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		GetPodsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
+			return pods, nil
+		},
+		GetPodLogsFunc: func(ctx context.Context, namespace string, podName string, opts corev1.PodLogOptions) ([]byte, error) {
+			return []byte(fmt.Sprintf("%s/%s previous=%t\n", podName, opts.Container, opts.Previous)), nil
+		},
+	}
+
+	collector := NewLogsCollector(mockClient, LogOptions{})
+	resources, err := collector.Collect(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, resources, 3) // init container, current and previous nginx
+
+	byName := make(map[string]ClusterResource)
+	for _, resource := range resources {
+		assert.Equal(t, "log", resource.Kind)
+		assert.Equal(t, "default", resource.Metadata["namespace"])
+		assert.Equal(t, "web-123", resource.Metadata["pod"])
+		byName[resource.Name] = resource
+	}
+
+	assert.Contains(t, byName, "web-123/migrate.log")
+	assert.Contains(t, byName, "web-123/nginx.log")
+	assert.Contains(t, byName, "web-123/nginx.previous.log")
+	assert.NotContains(t, byName, "web-123/sidecar.log")
+
+	previous := byName["web-123/nginx.previous.log"]
+	assert.Equal(t, "true", previous.Metadata["previous"])
+	assert.Equal(t, "nginx", previous.Metadata["container"])
+	assert.Equal(t, []byte("web-123/nginx previous=true\n"), previous.Data)
+}
+
+func TestLogsCollector_Collect_Options(t *testing.T) {
+	pods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-123", Namespace: "default"},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "nginx", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				},
+			},
+		},
+	}
+
+	var received corev1.PodLogOptions
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		GetPodsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
+			return pods, nil
+		},
+		GetPodLogsFunc: func(ctx context.Context, namespace string, podName string, opts corev1.PodLogOptions) ([]byte, error) {
+			received = opts
+			return []byte("0123456789"), nil
+		},
+	}
+
+	collector := NewLogsCollector(mockClient, LogOptions{
+		TailLines:  100,
+		Since:      30 * time.Minute,
+		LimitBytes: 10,
+	})
+	resources, err := collector.Collect(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, resources, 1)
+	assert.Equal(t, int64(100), *received.TailLines)
+	assert.Equal(t, int64(1800), *received.SinceSeconds)
+	assert.Equal(t, int64(10), *received.LimitBytes)
+	assert.Equal(t, "true", resources[0].Metadata["truncated"])
+}
+
+func TestLogsCollector_Collect_LogError(t *testing.T) {
+	pods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-123", Namespace: "default"},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "nginx", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				},
+			},
+		},
+	}
+
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		GetPodsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
+			return pods, nil
+		},
+		GetPodLogsFunc: func(ctx context.Context, namespace string, podName string, opts corev1.PodLogOptions) ([]byte, error) {
+			return nil, errors.New("failed to get logs")
+		},
+	}
+
+	collector := NewLogsCollector(mockClient, LogOptions{})
+	resources, err := collector.Collect(context.Background())
+
+	assert.Error(t, err)
+	assert.Nil(t, resources)
+	assert.Contains(t, err.Error(), "container nginx in pod default/web-123")
+}
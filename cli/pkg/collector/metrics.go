@@ -0,0 +1,159 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	"github.com/3nd3r1/kubin/cli/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+// MetricsStatus records whether usage metrics could be captured, so a
+// snapshot without metrics can be told apart from one without pods
+type MetricsStatus struct {
+	Available bool   `json:"available"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// MetricsCollector collects point in time pod and node usage from the
+// metrics.k8s.io API
+type MetricsCollector struct {
+	client kube.Client
+	kinds  KindFilter
+}
+
+func NewMetricsCollector(client kube.Client, kinds KindFilter) *MetricsCollector {
+	return &MetricsCollector{client: client, kinds: kinds}
+}
+
+func (c *MetricsCollector) Name() string {
+	return "metrics"
+}
+
+func (c *MetricsCollector) Kinds() []string {
+	return []string{"metricsstatus", "nodemetrics", "podmetrics"}
+}
+
+func (c *MetricsCollector) Collect(ctx context.Context) ([]ClusterResource, error) {
+	nodeMetrics, err := c.client.GetNodeMetrics(ctx, metav1.ListOptions{})
+	if metricsUnavailable(err) {
+		log.WithError(err).Warnw("Metrics API is not available, skipping usage metrics")
+		return []ClusterResource{metricsStatusResource(MetricsStatus{Reason: err.Error()})}, nil
+	}
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get node metrics: %w", err), "nodemetrics", "")
+	}
+
+	// The node metrics tell whether the API is served at all, the pod
+	// metrics and the pods for their limits are only read when captured.
+	// Without the pods the usage is kept, only the limit percentages are
+	// missing
+	var p partial
+	var podMetrics []metricsv1beta1.PodMetrics
+	var pods []corev1.Pod
+	if c.kinds.Matches(c.Name(), "podmetrics") {
+		podMetrics, err = c.client.GetPodMetrics(ctx, corev1.NamespaceAll, metav1.ListOptions{})
+		if err != nil {
+			if err := p.tolerate(fmt.Errorf("failed to get pod metrics: %w", err), "podmetrics", ""); err != nil {
+				return nil, err
+			}
+		} else {
+			pods, err = c.client.GetPods(ctx, corev1.NamespaceAll, metav1.ListOptions{})
+			if err != nil {
+				if err := p.tolerate(fmt.Errorf("failed to get pods for container limits: %w", err), "pod", ""); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	limits := make(map[string]corev1.ResourceList)
+	for _, pod := range pods {
+		for _, container := range pod.Spec.Containers {
+			limits[pod.Namespace+"/"+pod.Name+"/"+container.Name] = container.Resources.Limits
+		}
+	}
+
+	resources := []ClusterResource{metricsStatusResource(MetricsStatus{Available: true})}
+
+	for _, metrics := range nodeMetrics {
+		metadata := map[string]string{
+			"timestamp": metrics.Timestamp.UTC().Format(time.RFC3339),
+			"window":    metrics.Window.Duration.String(),
+		}
+		for name, quantity := range metrics.Usage {
+			metadata[string(name)] = quantity.String()
+		}
+
+		resources = append(resources, ClusterResource{
+			Kind:     "nodemetrics",
+			Group:    "metrics.k8s.io",
+			Version:  "v1beta1",
+			Name:     metrics.Name,
+			Data:     metrics,
+			Metadata: metadata,
+		})
+	}
+
+	for _, metrics := range podMetrics {
+		metadata := map[string]string{
+			"namespace": metrics.Namespace,
+			"timestamp": metrics.Timestamp.UTC().Format(time.RFC3339),
+			"window":    metrics.Window.Duration.String(),
+		}
+
+		for _, container := range metrics.Containers {
+			containerLimits := limits[metrics.Namespace+"/"+metrics.Name+"/"+container.Name]
+			for name, usage := range container.Usage {
+				metadata[string(name)+"."+container.Name] = usage.String()
+
+				if limit, exists := containerLimits[name]; exists && !limit.IsZero() {
+					metadata[string(name)+"LimitPercent."+container.Name] = limitPercent(usage, limit)
+				}
+			}
+		}
+
+		resources = append(resources, ClusterResource{
+			Kind:     "podmetrics",
+			Group:    "metrics.k8s.io",
+			Version:  "v1beta1",
+			Name:     metrics.Name,
+			Data:     metrics,
+			Metadata: metadata,
+		})
+	}
+
+	p.resources = resources
+	return p.result()
+}
+
+// metricsUnavailable reports whether an error means the metrics API is not
+// served at all, either because its APIService is missing or its backend is
+// down
+func metricsUnavailable(err error) bool {
+	return apierrors.IsNotFound(err) || apierrors.IsServiceUnavailable(err)
+}
+
+func metricsStatusResource(status MetricsStatus) ClusterResource {
+	return ClusterResource{
+		Kind: "metricsstatus",
+		Name: "metrics.k8s.io",
+		Data: status,
+		Metadata: map[string]string{
+			"available": strconv.FormatBool(status.Available),
+		},
+	}
+}
+
+// limitPercent returns how much of its limit a container uses, in percent
+func limitPercent(usage resource.Quantity, limit resource.Quantity) string {
+	percent := usage.AsApproximateFloat64() / limit.AsApproximateFloat64() * 100
+	return strconv.FormatFloat(percent, 'f', 1, 64)
+}
@@ -0,0 +1,168 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+func TestMetricsCollector_Name(t *testing.T) {
+	mockClient := &kube.MockClient{}
+	collector := NewMetricsCollector(mockClient, KindFilter{})
+
+	assert.Equal(t, "metrics", collector.Name())
+}
+
+func TestMetricsCollector_Collect_Success(t *testing.T) {
+	timestamp := metav1.NewTime(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	mockClient := &kube.MockClient{
+		GetNodeMetricsFunc: func(ctx context.Context, opts metav1.ListOptions) ([]metricsv1beta1.NodeMetrics, error) {
+			return []metricsv1beta1.NodeMetrics{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+					Timestamp:  timestamp,
+					Window:     metav1.Duration{Duration: 10 * time.Second},
+					Usage: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("1500m"),
+						corev1.ResourceMemory: resource.MustParse("6Gi"),
+					},
+				},
+			}, nil
+		},
+		GetPodMetricsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]metricsv1beta1.PodMetrics, error) {
+			assert.Equal(t, corev1.NamespaceAll, namespace)
+			return []metricsv1beta1.PodMetrics{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"},
+					Timestamp:  timestamp,
+					Window:     metav1.Duration{Duration: 10 * time.Second},
+					Containers: []metricsv1beta1.ContainerMetrics{
+						{
+							Name: "app",
+							Usage: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("100m"),
+								corev1.ResourceMemory: resource.MustParse("900Mi"),
+							},
+						},
+					},
+				},
+			}, nil
+		},
+		GetPodsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
+			return []corev1.Pod{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Name: "app",
+								Resources: corev1.ResourceRequirements{
+									Limits: corev1.ResourceList{
+										corev1.ResourceMemory: resource.MustParse("1Gi"),
+									},
+								},
+							},
+						},
+					},
+				},
+			}, nil
+		},
+	}
+
+	collector := NewMetricsCollector(mockClient, KindFilter{})
+	resources, err := collector.Collect(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, resources, 3)
+
+	status := resources[0]
+	assert.Equal(t, "metricsstatus", status.Kind)
+	assert.Equal(t, "true", status.Metadata["available"])
+
+	node := resources[1]
+	assert.Equal(t, "nodemetrics", node.Kind)
+	assert.Equal(t, "metrics.k8s.io", node.Group)
+	assert.Equal(t, "1500m", node.Metadata["cpu"])
+	assert.Equal(t, "6Gi", node.Metadata["memory"])
+	assert.Equal(t, "2025-01-01T12:00:00Z", node.Metadata["timestamp"])
+
+	pod := resources[2]
+	assert.Equal(t, "podmetrics", pod.Kind)
+	assert.Equal(t, "default", pod.Metadata["namespace"])
+	assert.Equal(t, "100m", pod.Metadata["cpu.app"])
+	assert.Equal(t, "900Mi", pod.Metadata["memory.app"])
+	assert.Equal(t, "87.9", pod.Metadata["memoryLimitPercent.app"])
+	assert.NotContains(t, pod.Metadata, "cpuLimitPercent.app")
+}
+
+func TestMetricsCollector_Collect_Unavailable(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetNodeMetricsFunc: func(ctx context.Context, opts metav1.ListOptions) ([]metricsv1beta1.NodeMetrics, error) {
+			return nil, apierrors.NewNotFound(schema.GroupResource{Group: "metrics.k8s.io", Resource: "nodes"}, "")
+		},
+	}
+
+	collector := NewMetricsCollector(mockClient, KindFilter{})
+	resources, err := collector.Collect(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, resources, 1)
+	assert.Equal(t, "metricsstatus", resources[0].Kind)
+	assert.Equal(t, "false", resources[0].Metadata["available"])
+	assert.NotEmpty(t, resources[0].Data.(MetricsStatus).Reason)
+}
+
+func TestMetricsCollector_Collect_Error(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetNodeMetricsFunc: func(ctx context.Context, opts metav1.ListOptions) ([]metricsv1beta1.NodeMetrics, error) {
+			return []metricsv1beta1.NodeMetrics{}, nil
+		},
+		GetPodMetricsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]metricsv1beta1.PodMetrics, error) {
+			return nil, errors.New("connection reset")
+		},
+	}
+
+	collector := NewMetricsCollector(mockClient, KindFilter{})
+	resources, err := collector.Collect(context.Background())
+
+	assert.Error(t, err)
+	assert.Nil(t, resources)
+	assert.Contains(t, err.Error(), "failed to get pod metrics")
+}
+
+func TestMetricsCollector_Collect_PodMetricsForbidden(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetNodeMetricsFunc: func(ctx context.Context, opts metav1.ListOptions) ([]metricsv1beta1.NodeMetrics, error) {
+			return []metricsv1beta1.NodeMetrics{{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}}, nil
+		},
+		GetPodMetricsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]metricsv1beta1.PodMetrics, error) {
+			return nil, apierrors.NewForbidden(schema.GroupResource{Group: "metrics.k8s.io", Resource: "pods"}, "", errors.New("no access"))
+		},
+	}
+
+	collector := NewMetricsCollector(mockClient, KindFilter{})
+	resources, err := collector.Collect(context.Background())
+
+	// The node metrics are kept, the pod metrics are recorded as failed
+	var failures Failures
+	assert.ErrorAs(t, err, &failures)
+	assert.Len(t, failures, 1)
+	assert.Equal(t, "podmetrics", failures[0].Kind)
+	assert.Equal(t, "Forbidden", failures[0].Reason)
+
+	assert.Len(t, resources, 2)
+	assert.Equal(t, "metricsstatus", resources[0].Kind)
+	assert.Equal(t, "nodemetrics", resources[1].Kind)
+	assert.Equal(t, "node-1", resources[1].Name)
+}
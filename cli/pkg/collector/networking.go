@@ -0,0 +1,353 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// gatewayResources are the namespaced Gateway API kinds collected when their
+// CRDs are installed, in order of preference per resource
+var gatewayResources = [][]schema.GroupVersionResource{
+	{
+		{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "gateways"},
+		{Group: "gateway.networking.k8s.io", Version: "v1beta1", Resource: "gateways"},
+	},
+	{
+		{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "httproutes"},
+		{Group: "gateway.networking.k8s.io", Version: "v1beta1", Resource: "httproutes"},
+	},
+}
+
+// NetworkingCollector collects services and everything that routes traffic to
+// them
+type NetworkingCollector struct {
+	client kube.Client
+	kinds  KindFilter
+}
+
+func NewNetworkingCollector(client kube.Client, kinds KindFilter) *NetworkingCollector {
+	return &NetworkingCollector{client: client, kinds: kinds}
+}
+
+func (c *NetworkingCollector) Name() string {
+	return "networking"
+}
+
+func (c *NetworkingCollector) Kinds() []string {
+	return []string{"service", "endpointslice", "endpoints", "ingress", "ingressclass", "networkpolicy", "gateway", "httproute"}
+}
+
+func (c *NetworkingCollector) Collect(ctx context.Context) ([]ClusterResource, error) {
+	namespaces, err := c.client.GetNamespaces(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get namespaces for networking collection: %w", err), "namespace", "")
+	}
+
+	var p partial
+	if err := p.add(collectEach(ctx, namespaces, c.collectNamespace)); err != nil {
+		return nil, err
+	}
+	if c.kinds.Matches(c.Name(), "ingressclass") {
+		if err := p.add(c.collectIngressClasses(ctx)); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.add(c.collectGatewayResources(ctx)); err != nil {
+		return nil, err
+	}
+
+	return p.result()
+}
+
+func (c *NetworkingCollector) collectNamespace(ctx context.Context, namespace corev1.Namespace) ([]ClusterResource, error) {
+	var p partial
+	if err := p.add(c.collectServices(ctx, namespace.Name)); err != nil {
+		return nil, err
+	}
+	if c.kinds.Matches(c.Name(), "ingress") {
+		if err := p.add(c.collectIngresses(ctx, namespace.Name)); err != nil {
+			return nil, err
+		}
+	}
+	if c.kinds.Matches(c.Name(), "networkpolicy") {
+		if err := p.add(c.collectNetworkPolicies(ctx, namespace.Name)); err != nil {
+			return nil, err
+		}
+	}
+
+	return p.result()
+}
+
+// collectServices collects the services of a namespace together with their
+// endpoint slices and legacy endpoints, annotating each service with the
+// number of endpoints backing it. Endpoints are only counted when one of
+// their kinds is captured
+func (c *NetworkingCollector) collectServices(ctx context.Context, namespace string) ([]ClusterResource, error) {
+	var resources []ClusterResource
+	var p partial
+	var err error
+
+	var services []corev1.Service
+	if c.kinds.Matches(c.Name(), "service") {
+		services, err = c.client.GetServices(ctx, namespace, metav1.ListOptions{})
+		if err != nil {
+			return nil, tolerate(fmt.Errorf("failed to get services from namespace %s: %w", namespace, err), "service", namespace)
+		}
+	}
+
+	// The services are still worth having without their endpoint counts
+	var endpointSlices []discoveryv1.EndpointSlice
+	if c.kinds.Matches(c.Name(), "endpointslice") {
+		endpointSlices, err = c.client.GetEndpointSlices(ctx, namespace, metav1.ListOptions{})
+		if err != nil {
+			if err := p.tolerate(fmt.Errorf("failed to get endpointslices from namespace %s: %w", namespace, err), "endpointslice", namespace); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var endpoints []corev1.Endpoints
+	if c.kinds.Matches(c.Name(), "endpoints") {
+		endpoints, err = c.client.GetEndpoints(ctx, namespace, metav1.ListOptions{})
+		if err != nil {
+			if err := p.tolerate(fmt.Errorf("failed to get endpoints from namespace %s: %w", namespace, err), "endpoints", namespace); err != nil {
+				return nil, err
+			}
+		}
+	}
+	counted := c.kinds.Matches(c.Name(), "endpointslice") || c.kinds.Matches(c.Name(), "endpoints")
+
+	ready := make(map[string]int)
+	notReady := make(map[string]int)
+	hasSlices := make(map[string]bool)
+	for _, slice := range endpointSlices {
+		service := slice.Labels[discoveryv1.LabelServiceName]
+		if service != "" {
+			hasSlices[service] = true
+			sliceReady, sliceNotReady := countSliceEndpoints(slice)
+			ready[service] += sliceReady
+			notReady[service] += sliceNotReady
+		}
+
+		resources = append(resources, ClusterResource{
+			Kind:    "endpointslice",
+			Group:   "discovery.k8s.io",
+			Version: "v1",
+			Name:    slice.Name,
+			Data:    slice,
+			Metadata: map[string]string{
+				"namespace": namespace,
+				"service":   service,
+			},
+		})
+	}
+
+	for _, endpoint := range endpoints {
+		// Endpoint slices are authoritative, legacy endpoints only fill in
+		// for clusters that don't mirror them
+		if !hasSlices[endpoint.Name] {
+			for _, subset := range endpoint.Subsets {
+				ready[endpoint.Name] += len(subset.Addresses)
+				notReady[endpoint.Name] += len(subset.NotReadyAddresses)
+			}
+		}
+
+		resources = append(resources, ClusterResource{
+			Kind:    "endpoints",
+			Version: "v1",
+			Name:    endpoint.Name,
+			Data:    endpoint,
+			Metadata: map[string]string{
+				"namespace": namespace,
+				"service":   endpoint.Name,
+			},
+		})
+	}
+
+	for _, service := range services {
+		metadata := map[string]string{
+			"namespace": namespace,
+			"type":      string(service.Spec.Type),
+		}
+		if counted {
+			metadata["readyEndpoints"] = strconv.Itoa(ready[service.Name])
+			metadata["notReadyEndpoints"] = strconv.Itoa(notReady[service.Name])
+		}
+		// Services without a selector are backed by manually managed
+		// endpoints, so having none is not necessarily a problem
+		metadata["hasSelector"] = strconv.FormatBool(len(service.Spec.Selector) > 0)
+		if service.Spec.Type == corev1.ServiceTypeExternalName {
+			metadata["externalName"] = service.Spec.ExternalName
+		}
+
+		resources = append(resources, ClusterResource{
+			Kind:     "service",
+			Version:  "v1",
+			Name:     service.Name,
+			Data:     service,
+			Metadata: metadata,
+		})
+	}
+
+	p.resources = resources
+	return p.result()
+}
+
+func (c *NetworkingCollector) collectIngresses(ctx context.Context, namespace string) ([]ClusterResource, error) {
+	var resources []ClusterResource
+
+	ingresses, err := c.client.GetIngresses(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get ingresses from namespace %s: %w", namespace, err), "ingress", namespace)
+	}
+
+	for _, ingress := range ingresses {
+		metadata := map[string]string{
+			"namespace": namespace,
+		}
+		if ingress.Spec.IngressClassName != nil {
+			metadata["ingressClass"] = *ingress.Spec.IngressClassName
+		}
+
+		var backends []string
+		if ingress.Spec.DefaultBackend != nil && ingress.Spec.DefaultBackend.Service != nil {
+			backends = append(backends, ingress.Spec.DefaultBackend.Service.Name)
+		}
+		for _, rule := range ingress.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				if path.Backend.Service != nil {
+					backends = append(backends, path.Backend.Service.Name)
+				}
+			}
+		}
+		metadata["services"] = strings.Join(backends, ",")
+
+		resources = append(resources, ClusterResource{
+			Kind:     "ingress",
+			Group:    "networking.k8s.io",
+			Version:  "v1",
+			Name:     ingress.Name,
+			Data:     ingress,
+			Metadata: metadata,
+		})
+	}
+
+	return resources, nil
+}
+
+func (c *NetworkingCollector) collectIngressClasses(ctx context.Context) ([]ClusterResource, error) {
+	var resources []ClusterResource
+
+	ingressClasses, err := c.client.GetIngressClasses(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get ingressclasses: %w", err), "ingressclass", "")
+	}
+
+	for _, ingressClass := range ingressClasses {
+		resources = append(resources, ClusterResource{
+			Kind:    "ingressclass",
+			Group:   "networking.k8s.io",
+			Version: "v1",
+			Name:    ingressClass.Name,
+			Data:    ingressClass,
+			Metadata: map[string]string{
+				"controller": ingressClass.Spec.Controller,
+			},
+		})
+	}
+
+	return resources, nil
+}
+
+func (c *NetworkingCollector) collectNetworkPolicies(ctx context.Context, namespace string) ([]ClusterResource, error) {
+	var resources []ClusterResource
+
+	networkPolicies, err := c.client.GetNetworkPolicies(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get networkpolicies from namespace %s: %w", namespace, err), "networkpolicy", namespace)
+	}
+
+	for _, networkPolicy := range networkPolicies {
+		resources = append(resources, ClusterResource{
+			Kind:    "networkpolicy",
+			Group:   "networking.k8s.io",
+			Version: "v1",
+			Name:    networkPolicy.Name,
+			Data:    networkPolicy,
+			Metadata: map[string]string{
+				"namespace": namespace,
+			},
+		})
+	}
+
+	return resources, nil
+}
+
+// collectGatewayResources collects Gateway API objects, clusters without the
+// Gateway API CRDs simply have none
+func (c *NetworkingCollector) collectGatewayResources(ctx context.Context) ([]ClusterResource, error) {
+	var resources []ClusterResource
+	var p partial
+
+	for _, versions := range gatewayResources {
+		if !c.kinds.Matches(c.Name(), strings.TrimSuffix(versions[0].Resource, "s")) {
+			continue
+		}
+
+		for _, gvr := range versions {
+			items, err := c.client.ListResources(ctx, gvr, true, "", metav1.ListOptions{})
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				kind := strings.TrimSuffix(gvr.Resource, "s")
+				if err := p.tolerate(fmt.Errorf("failed to list %s: %w", gvr.String(), err), kind, ""); err != nil {
+					return nil, err
+				}
+				break
+			}
+
+			for _, item := range items {
+				resources = append(resources, ClusterResource{
+					Kind:    strings.ToLower(item.GetKind()),
+					Group:   gvr.Group,
+					Version: gvr.Version,
+					Name:    item.GetName(),
+					Data:    item.Object,
+					Metadata: map[string]string{
+						"namespace": item.GetNamespace(),
+					},
+				})
+			}
+			break
+		}
+	}
+
+	p.resources = resources
+	return p.result()
+}
+
+func countSliceEndpoints(slice discoveryv1.EndpointSlice) (int, int) {
+	var ready, notReady int
+	for _, endpoint := range slice.Endpoints {
+		// A nil ready condition must be interpreted as ready
+		if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+			ready++
+		} else {
+			notReady++
+		}
+	}
+	return ready, notReady
+}
@@ -0,0 +1,231 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestNetworkingCollector_Name(t *testing.T) {
+	mockClient := &kube.MockClient{}
+	collector := NewNetworkingCollector(mockClient, KindFilter{})
+
+	assert.Equal(t, "networking", collector.Name())
+}
+
+func TestNetworkingCollector_Collect_ServiceEndpoints(t *testing.T) {
+	notReady := false
+
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		GetServicesFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Service, error) {
+			return []corev1.Service{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: namespace},
+					Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "web"}},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "legacy", Namespace: namespace},
+					Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "legacy"}},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "broken", Namespace: namespace},
+					Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "missing"}},
+				},
+			}, nil
+		},
+		GetEndpointSlicesFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]discoveryv1.EndpointSlice, error) {
+			return []discoveryv1.EndpointSlice{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "web-abc",
+						Namespace: namespace,
+						Labels:    map[string]string{discoveryv1.LabelServiceName: "web"},
+					},
+					Endpoints: []discoveryv1.Endpoint{
+						{Addresses: []string{"10.0.0.1"}},
+						{Addresses: []string{"10.0.0.2"}},
+						{Addresses: []string{"10.0.0.3"}, Conditions: discoveryv1.EndpointConditions{Ready: &notReady}},
+					},
+				},
+			}, nil
+		},
+		GetEndpointsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Endpoints, error) {
+			return []corev1.Endpoints{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: namespace},
+					Subsets:    []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}}},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "legacy", Namespace: namespace},
+					Subsets:    []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{IP: "10.0.1.1"}}}},
+				},
+			}, nil
+		},
+	}
+
+	collector := NewNetworkingCollector(mockClient, KindFilter{Include: []string{"service", "endpoint*"}})
+	resources, err := collector.Collect(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, resources, 6) // 3 services, 1 endpointslice, 2 endpoints
+
+	services := make(map[string]ClusterResource)
+	for _, resource := range resources {
+		if resource.Kind == "service" {
+			services[resource.Name] = resource
+		}
+	}
+
+	// Endpoint slices take precedence over legacy endpoints
+	assert.Equal(t, "2", services["web"].Metadata["readyEndpoints"])
+	assert.Equal(t, "1", services["web"].Metadata["notReadyEndpoints"])
+	assert.Equal(t, "1", services["legacy"].Metadata["readyEndpoints"])
+	assert.Equal(t, "0", services["broken"].Metadata["readyEndpoints"])
+	assert.Equal(t, "true", services["broken"].Metadata["hasSelector"])
+}
+
+func TestNetworkingCollector_Collect_Ingresses(t *testing.T) {
+	className := "nginx"
+
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		GetIngressesFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]networkingv1.Ingress, error) {
+			return []networkingv1.Ingress{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: namespace},
+					Spec: networkingv1.IngressSpec{
+						IngressClassName: &className,
+						Rules: []networkingv1.IngressRule{
+							{
+								IngressRuleValue: networkingv1.IngressRuleValue{
+									HTTP: &networkingv1.HTTPIngressRuleValue{
+										Paths: []networkingv1.HTTPIngressPath{
+											{Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "web"}}},
+											{Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "api"}}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}, nil
+		},
+		GetIngressClassesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]networkingv1.IngressClass, error) {
+			return []networkingv1.IngressClass{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+					Spec:       networkingv1.IngressClassSpec{Controller: "k8s.io/ingress-nginx"},
+				},
+			}, nil
+		},
+	}
+
+	collector := NewNetworkingCollector(mockClient, KindFilter{Include: []string{"ingress*"}})
+	resources, err := collector.Collect(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, resources, 2)
+
+	for _, resource := range resources {
+		switch resource.Kind {
+		case "ingress":
+			assert.Equal(t, "nginx", resource.Metadata["ingressClass"])
+			assert.Equal(t, "web,api", resource.Metadata["services"])
+		case "ingressclass":
+			assert.Equal(t, "k8s.io/ingress-nginx", resource.Metadata["controller"])
+			assert.NotContains(t, resource.Metadata, "namespace")
+		default:
+			t.Errorf("Unexpected resource kind: %s", resource.Kind)
+		}
+	}
+}
+
+func TestNetworkingCollector_Collect_GatewayAPI(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		ListResourcesFunc: func(ctx context.Context, gvr schema.GroupVersionResource, namespaced bool, namespace string, opts metav1.ListOptions) ([]unstructured.Unstructured, error) {
+			// Only the beta API of gateways is served
+			if gvr.Resource == "gateways" && gvr.Version == "v1beta1" {
+				return []unstructured.Unstructured{
+					newUnstructured("gateway.networking.k8s.io/v1beta1", "Gateway", "default", "public"),
+				}, nil
+			}
+			return nil, apierrors.NewNotFound(gvr.GroupResource(), "")
+		},
+	}
+
+	collector := NewNetworkingCollector(mockClient, KindFilter{Include: []string{"gateway", "httproute"}})
+	resources, err := collector.Collect(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, resources, 1)
+	assert.Equal(t, "gateway", resources[0].Kind)
+	assert.Equal(t, "gateway.networking.k8s.io", resources[0].Group)
+	assert.Equal(t, "v1beta1", resources[0].Version)
+	assert.Equal(t, "default", resources[0].Metadata["namespace"])
+}
+
+func TestNetworkingCollector_Collect_ServiceError(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		GetServicesFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Service, error) {
+			return nil, errors.New("failed to get services")
+		},
+	}
+
+	collector := NewNetworkingCollector(mockClient, KindFilter{Include: []string{"service"}})
+	resources, err := collector.Collect(context.Background())
+
+	assert.Error(t, err)
+	assert.Nil(t, resources)
+	assert.Contains(t, err.Error(), "failed to get services")
+}
+
+func TestNetworkingCollector_Collect_WithoutEndpoints(t *testing.T) {
+	listed := false
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		GetServicesFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Service, error) {
+			return []corev1.Service{{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}}, nil
+		},
+		GetEndpointSlicesFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]discoveryv1.EndpointSlice, error) {
+			listed = true
+			return []discoveryv1.EndpointSlice{}, nil
+		},
+		GetEndpointsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Endpoints, error) {
+			listed = true
+			return []corev1.Endpoints{}, nil
+		},
+	}
+
+	collector := NewNetworkingCollector(mockClient, KindFilter{Include: []string{"service"}})
+	resources, err := collector.Collect(context.Background())
+
+	assert.NoError(t, err)
+	assert.False(t, listed)
+	assert.Len(t, resources, 1)
+	// Without the endpoints the counts are unknown rather than zero
+	assert.NotContains(t, resources[0].Metadata, "readyEndpoints")
+}
@@ -0,0 +1,181 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodesCollector collects nodes with their scheduling relevant state and the
+// kubelet stats summary of each node
+type NodesCollector struct {
+	client kube.Client
+	kinds  KindFilter
+}
+
+func NewNodesCollector(client kube.Client, kinds KindFilter) *NodesCollector {
+	return &NodesCollector{client: client, kinds: kinds}
+}
+
+func (c *NodesCollector) Name() string {
+	return "nodes"
+}
+
+func (c *NodesCollector) Kinds() []string {
+	return []string{"node", "nodestats"}
+}
+
+func (c *NodesCollector) Collect(ctx context.Context) ([]ClusterResource, error) {
+	nodes, err := c.client.GetNodes(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get nodes: %w", err), "node", "")
+	}
+
+	// Without the pods the nodes are still captured, only their requests
+	// are missing
+	var p partial
+	var pods []corev1.Pod
+	if c.kinds.Matches(c.Name(), "node") {
+		pods, err = c.client.GetPods(ctx, corev1.NamespaceAll, metav1.ListOptions{})
+		if err != nil {
+			if err := p.tolerate(fmt.Errorf("failed to get pods for node resource requests: %w", err), "pod", ""); err != nil {
+				return nil, err
+			}
+		}
+	}
+	withStats := c.kinds.Matches(c.Name(), "nodestats")
+
+	requests := make(map[string]corev1.ResourceList)
+	podCounts := make(map[string]int)
+	for _, pod := range pods {
+		// Finished pods no longer hold on to their requests
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		if _, exists := requests[pod.Spec.NodeName]; !exists {
+			requests[pod.Spec.NodeName] = corev1.ResourceList{}
+		}
+		addResourceList(requests[pod.Spec.NodeName], podRequests(pod))
+		podCounts[pod.Spec.NodeName]++
+	}
+
+	nodeResources, err := collectEach(ctx, nodes, func(ctx context.Context, node corev1.Node) ([]ClusterResource, error) {
+		metadata := nodeMetadata(node)
+		metadata["pods"] = strconv.Itoa(podCounts[node.Name])
+		for name, quantity := range requests[node.Name] {
+			metadata["requested."+string(name)] = quantity.String()
+		}
+
+		resources := []ClusterResource{{
+			Kind:     "node",
+			Version:  "v1",
+			Name:     node.Name,
+			Data:     node,
+			Metadata: metadata,
+		}}
+
+		if !withStats {
+			return resources, nil
+		}
+
+		// The kubelet of a broken node is often unreachable, which is
+		// exactly when the node itself matters most
+		summary, err := c.client.GetNodeStatsSummary(ctx, node.Name)
+		if err != nil {
+			reason, _ := failureReason(err)
+			return resources, Failures{{
+				Kind:    "nodestats",
+				Name:    node.Name,
+				Reason:  reason,
+				Message: fmt.Sprintf("failed to get kubelet stats summary of node %s: %s", node.Name, err),
+			}}
+		}
+
+		return append(resources, ClusterResource{
+			Kind: "nodestats",
+			Name: node.Name,
+			Data: summary,
+			Metadata: map[string]string{
+				"node": node.Name,
+			},
+		}), nil
+	})
+	if err := p.add(nodeResources, err); err != nil {
+		return nil, err
+	}
+
+	return p.result()
+}
+
+// nodeMetadata records conditions, taints and allocatable resources of a node
+func nodeMetadata(node corev1.Node) map[string]string {
+	metadata := map[string]string{
+		"unschedulable": strconv.FormatBool(node.Spec.Unschedulable),
+	}
+
+	for _, condition := range node.Status.Conditions {
+		switch condition.Type {
+		case corev1.NodeReady, corev1.NodeMemoryPressure, corev1.NodeDiskPressure,
+			corev1.NodePIDPressure, corev1.NodeNetworkUnavailable:
+			metadata["condition."+string(condition.Type)] = string(condition.Status)
+		}
+	}
+
+	var taints []string
+	for _, taint := range node.Spec.Taints {
+		if taint.Value != "" {
+			taints = append(taints, fmt.Sprintf("%s=%s:%s", taint.Key, taint.Value, taint.Effect))
+		} else {
+			taints = append(taints, fmt.Sprintf("%s:%s", taint.Key, taint.Effect))
+		}
+	}
+	metadata["taints"] = strings.Join(taints, ",")
+
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourcePods, corev1.ResourceEphemeralStorage} {
+		if quantity, exists := node.Status.Allocatable[name]; exists {
+			metadata["allocatable."+string(name)] = quantity.String()
+		}
+	}
+
+	return metadata
+}
+
+// podRequests returns the resources the scheduler reserves for a pod: the
+// larger of its containers combined and its biggest init container, plus
+// the pod overhead
+func podRequests(pod corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		addResourceList(requests, container.Resources.Requests)
+	}
+
+	for _, container := range pod.Spec.InitContainers {
+		for name, quantity := range container.Resources.Requests {
+			if current, exists := requests[name]; !exists || quantity.Cmp(current) > 0 {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+
+	addResourceList(requests, pod.Spec.Overhead)
+
+	return requests
+}
+
+func addResourceList(list corev1.ResourceList, add corev1.ResourceList) {
+	for name, quantity := range add {
+		current, exists := list[name]
+		if !exists {
+			list[name] = quantity.DeepCopy()
+			continue
+		}
+		current.Add(quantity)
+		list[name] = current
+	}
+}
@@ -0,0 +1,168 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestPod(name string, nodeName string, phase corev1.PodPhase, cpu string, memory string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+			Containers: []corev1.Container{
+				{
+					Name: "app",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse(cpu),
+							corev1.ResourceMemory: resource.MustParse(memory),
+						},
+					},
+				},
+			},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestNodesCollector_Name(t *testing.T) {
+	mockClient := &kube.MockClient{}
+	collector := NewNodesCollector(mockClient, KindFilter{})
+
+	assert.Equal(t, "nodes", collector.Name())
+}
+
+func TestNodesCollector_Collect_Success(t *testing.T) {
+	nodes := []corev1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Spec: corev1.NodeSpec{
+				Taints: []corev1.Taint{
+					{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+					{Key: "node.kubernetes.io/disk-pressure", Effect: corev1.TaintEffectNoSchedule},
+				},
+			},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+					{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue},
+				},
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("4"),
+					corev1.ResourceMemory: resource.MustParse("8Gi"),
+				},
+			},
+		},
+	}
+
+	mockClient := &kube.MockClient{
+		GetNodesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Node, error) {
+			return nodes, nil
+		},
+		GetPodsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
+			assert.Equal(t, corev1.NamespaceAll, namespace)
+			return []corev1.Pod{
+				newTestPod("web-1", "node-1", corev1.PodRunning, "500m", "1Gi"),
+				newTestPod("web-2", "node-1", corev1.PodRunning, "250m", "512Mi"),
+				newTestPod("done", "node-1", corev1.PodSucceeded, "2", "4Gi"),
+				newTestPod("pending", "", corev1.PodPending, "1", "1Gi"),
+			}, nil
+		},
+		GetNodeStatsSummaryFunc: func(ctx context.Context, nodeName string) (json.RawMessage, error) {
+			return json.RawMessage(`{"node":{"nodeName":"node-1"}}`), nil
+		},
+	}
+
+	collector := NewNodesCollector(mockClient, KindFilter{})
+	resources, err := collector.Collect(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, resources, 2)
+
+	node := resources[0]
+	assert.Equal(t, "node", node.Kind)
+	assert.Equal(t, "True", node.Metadata["condition.Ready"])
+	assert.Equal(t, "True", node.Metadata["condition.DiskPressure"])
+	assert.Equal(t, "dedicated=gpu:NoSchedule,node.kubernetes.io/disk-pressure:NoSchedule", node.Metadata["taints"])
+	assert.Equal(t, "4", node.Metadata["allocatable.cpu"])
+	assert.Equal(t, "8Gi", node.Metadata["allocatable.memory"])
+	assert.Equal(t, "750m", node.Metadata["requested.cpu"])
+	assert.Equal(t, "1536Mi", node.Metadata["requested.memory"])
+	assert.Equal(t, "2", node.Metadata["pods"])
+
+	stats := resources[1]
+	assert.Equal(t, "nodestats", stats.Kind)
+	assert.Equal(t, "node-1", stats.Name)
+}
+
+func TestNodesCollector_Collect_StatsUnavailable(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetNodesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Node, error) {
+			return []corev1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}}, nil
+		},
+		GetPodsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
+			return []corev1.Pod{}, nil
+		},
+		GetNodeStatsSummaryFunc: func(ctx context.Context, nodeName string) (json.RawMessage, error) {
+			return nil, errors.New("kubelet unreachable")
+		},
+	}
+
+	collector := NewNodesCollector(mockClient, KindFilter{})
+	resources, err := collector.Collect(context.Background())
+
+	// The node is still captured without its stats, which are recorded as
+	// a failure
+	var failures Failures
+	assert.ErrorAs(t, err, &failures)
+	assert.Len(t, failures, 1)
+	assert.Equal(t, "nodestats", failures[0].Kind)
+	assert.Equal(t, "node-1", failures[0].Name)
+	assert.Len(t, resources, 1)
+	assert.Equal(t, "node", resources[0].Kind)
+}
+
+func TestNodesCollector_Collect_NodeError(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetNodesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Node, error) {
+			return nil, errors.New("failed to get nodes")
+		},
+	}
+
+	collector := NewNodesCollector(mockClient, KindFilter{})
+	resources, err := collector.Collect(context.Background())
+
+	assert.Error(t, err)
+	assert.Nil(t, resources)
+	assert.Contains(t, err.Error(), "failed to get nodes")
+}
+
+func TestPodRequests_InitContainers(t *testing.T) {
+	pod := newTestPod("web", "node-1", corev1.PodRunning, "500m", "1Gi")
+	pod.Spec.InitContainers = []corev1.Container{
+		{
+			Name: "migrate",
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("2"),
+				},
+			},
+		},
+	}
+
+	requests := podRequests(pod)
+	cpu := requests[corev1.ResourceCPU]
+	memory := requests[corev1.ResourceMemory]
+
+	assert.Equal(t, "2", cpu.String())
+	assert.Equal(t, "1Gi", memory.String())
+}
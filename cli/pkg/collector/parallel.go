@@ -0,0 +1,106 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// defaultWorkers is how many items forEach works on at once when the
+// context does not say, the default concurrency of kubin create
+const defaultWorkers = 10
+
+type workersKey struct{}
+
+// WithWorkers sets how many items forEach works on at once for collectors
+// run with the returned context
+func WithWorkers(ctx context.Context, workers int) context.Context {
+	return context.WithValue(ctx, workersKey{}, workers)
+}
+
+func workersOf(ctx context.Context) int {
+	if workers, ok := ctx.Value(workersKey{}).(int); ok && workers > 0 {
+		return workers
+	}
+	return defaultWorkers
+}
+
+// forEach calls fn for every item on a bounded pool of workers and returns
+// the results in the order of the items, so the output never depends on
+// scheduling. Failures are gathered and returned along with the results,
+// any other error cancels the remaining calls and is returned
+func forEach[T any, R any](ctx context.Context, items []T, fn func(ctx context.Context, item T) (R, error)) ([]R, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]R, len(items))
+	failures := make([]Failures, len(items))
+
+	var (
+		wg       sync.WaitGroup
+		next     atomic.Int64
+		aborted  atomic.Bool
+		once     sync.Once
+		firstErr error
+	)
+	for range min(workersOf(ctx), len(items)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for !aborted.Load() {
+				i := int(next.Add(1) - 1)
+				if i >= len(items) {
+					return
+				}
+
+				result, err := fn(ctx, items[i])
+				if errors.As(err, &failures[i]) {
+					err = nil
+				}
+				if err != nil {
+					// Later failures are mostly caused by the cancellation
+					once.Do(func() {
+						firstErr = err
+						aborted.Store(true)
+						cancel()
+					})
+					return
+				}
+				results[i] = result
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	var all Failures
+	for _, itemFailures := range failures {
+		all = append(all, itemFailures...)
+	}
+	if len(all) > 0 {
+		return results, all
+	}
+
+	return results, nil
+}
+
+// collectEach is forEach for functions collecting resources, the resources
+// of every item are concatenated in the order of the items
+func collectEach[T any](ctx context.Context, items []T, collect func(ctx context.Context, item T) ([]ClusterResource, error)) ([]ClusterResource, error) {
+	results, err := forEach(ctx, items, collect)
+
+	var p partial
+	if err := p.add(nil, err); err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		p.resources = append(p.resources, result...)
+	}
+
+	return p.result()
+}
@@ -0,0 +1,89 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForEach_KeepsOrder(t *testing.T) {
+	items := []int{5, 1, 4, 2, 3}
+
+	results, err := forEach(context.Background(), items, func(ctx context.Context, item int) (int, error) {
+		// Later items finish first
+		time.Sleep(time.Duration(item) * time.Millisecond)
+		return item * 10, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{50, 10, 40, 20, 30}, results)
+}
+
+func TestForEach_ReturnsErrorAndCancels(t *testing.T) {
+	failure := errors.New("list failed")
+
+	_, err := forEach(context.Background(), []string{"fails", "waits"}, func(ctx context.Context, item string) (string, error) {
+		if item == "fails" {
+			return "", failure
+		}
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+
+	assert.ErrorIs(t, err, failure)
+}
+
+func TestCollectEach_FlattensInOrder(t *testing.T) {
+	resources, err := collectEach(context.Background(), []string{"a", "b"}, func(ctx context.Context, item string) ([]ClusterResource, error) {
+		return []ClusterResource{
+			{Kind: "pod", Name: item + "-1"},
+			{Kind: "pod", Name: item + "-2"},
+		}, nil
+	})
+
+	assert.NoError(t, err)
+	var names []string
+	for _, resource := range resources {
+		names = append(names, resource.Name)
+	}
+	assert.Equal(t, []string{"a-1", "a-2", "b-1", "b-2"}, names)
+}
+
+func TestForEach_KeepsGoingAfterFailures(t *testing.T) {
+	results, err := forEach(context.Background(), []string{"team-a", "restricted", "team-b"}, func(ctx context.Context, namespace string) (string, error) {
+		if namespace == "restricted" {
+			return "", Failures{{Kind: "pod", Namespace: namespace, Reason: "Forbidden"}}
+		}
+		return namespace, nil
+	})
+
+	var failures Failures
+	assert.ErrorAs(t, err, &failures)
+	assert.Len(t, failures, 1)
+	assert.Equal(t, []string{"team-a", "", "team-b"}, results)
+}
+
+func TestForEach_BoundsWorkers(t *testing.T) {
+	items := make([]int, 50)
+	var running, most atomic.Int32
+
+	_, err := forEach(WithWorkers(context.Background(), 3), items, func(ctx context.Context, item int) (int, error) {
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			seen := most.Load()
+			if current <= seen || most.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		return item, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), most.Load())
+}
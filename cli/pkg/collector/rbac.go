@@ -0,0 +1,376 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ServiceAccountPermissions summarizes every rule granted to a service
+// account, directly or through one of the groups it belongs to
+type ServiceAccountPermissions struct {
+	Namespace      string        `json:"namespace"`
+	ServiceAccount string        `json:"serviceAccount"`
+	Rules          []GrantedRule `json:"rules"`
+}
+
+// GrantedRule is a policy rule together with the binding that grants it
+type GrantedRule struct {
+	// Namespace the rule applies to, empty for cluster wide rules
+	Namespace string `json:"namespace,omitempty"`
+	Binding   string `json:"binding"`
+	Role      string `json:"role"`
+	// Subject is the binding subject matching the service account
+	Subject string `json:"subject"`
+
+	rbacv1.PolicyRule `json:",inline"`
+}
+
+// RBACCollector collects roles, bindings and service accounts, and derives the
+// effective permissions of each service account
+type RBACCollector struct {
+	client kube.Client
+	kinds  KindFilter
+}
+
+func NewRBACCollector(client kube.Client, kinds KindFilter) *RBACCollector {
+	return &RBACCollector{client: client, kinds: kinds}
+}
+
+func (c *RBACCollector) Name() string {
+	return "rbac"
+}
+
+func (c *RBACCollector) Kinds() []string {
+	return []string{"clusterrole", "clusterrolebinding", "role", "rolebinding", "serviceaccount", "serviceaccountpermissions"}
+}
+
+// rbacBinding is the common shape of role bindings and cluster role bindings
+type rbacBinding struct {
+	name      string
+	namespace string
+	roleRef   rbacv1.RoleRef
+	subjects  []rbacv1.Subject
+}
+
+// namespaceRBAC holds the RBAC objects listed from a single namespace
+type namespaceRBAC struct {
+	roles           []rbacv1.Role
+	roleBindings    []rbacv1.RoleBinding
+	serviceAccounts []corev1.ServiceAccount
+}
+
+// needs reports whether a kind has to be listed, the permissions of the
+// service accounts are resolved from every other kind
+func (c *RBACCollector) needs(kind string) bool {
+	return c.kinds.Matches(c.Name(), kind) || c.kinds.Matches(c.Name(), "serviceaccountpermissions")
+}
+
+func (c *RBACCollector) collectNamespace(ctx context.Context, namespace corev1.Namespace) (namespaceRBAC, error) {
+	var collected namespaceRBAC
+	var p partial
+	var err error
+
+	if c.needs("role") {
+		collected.roles, err = c.client.GetRoles(ctx, namespace.Name, metav1.ListOptions{})
+		if err != nil {
+			if err := p.tolerate(fmt.Errorf("failed to get roles from namespace %s: %w", namespace.Name, err), "role", namespace.Name); err != nil {
+				return collected, err
+			}
+		}
+	}
+
+	if c.needs("rolebinding") {
+		collected.roleBindings, err = c.client.GetRoleBindings(ctx, namespace.Name, metav1.ListOptions{})
+		if err != nil {
+			if err := p.tolerate(fmt.Errorf("failed to get rolebindings from namespace %s: %w", namespace.Name, err), "rolebinding", namespace.Name); err != nil {
+				return collected, err
+			}
+		}
+	}
+
+	if c.needs("serviceaccount") {
+		collected.serviceAccounts, err = c.client.GetServiceAccounts(ctx, namespace.Name, metav1.ListOptions{})
+		if err != nil {
+			if err := p.tolerate(fmt.Errorf("failed to get serviceaccounts from namespace %s: %w", namespace.Name, err), "serviceaccount", namespace.Name); err != nil {
+				return collected, err
+			}
+		}
+	}
+
+	_, err = p.result()
+	return collected, err
+}
+
+func (c *RBACCollector) Collect(ctx context.Context) ([]ClusterResource, error) {
+	var resources []ClusterResource
+
+	// Permissions are still resolved from whatever could be listed, the
+	// failures tell which part of them may be missing
+	var p partial
+	var err error
+
+	var clusterRoles []rbacv1.ClusterRole
+	if c.needs("clusterrole") {
+		clusterRoles, err = c.client.GetClusterRoles(ctx, metav1.ListOptions{})
+		if err != nil {
+			if err := p.tolerate(fmt.Errorf("failed to get clusterroles: %w", err), "clusterrole", ""); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var clusterRoleBindings []rbacv1.ClusterRoleBinding
+	if c.needs("clusterrolebinding") {
+		clusterRoleBindings, err = c.client.GetClusterRoleBindings(ctx, metav1.ListOptions{})
+		if err != nil {
+			if err := p.tolerate(fmt.Errorf("failed to get clusterrolebindings: %w", err), "clusterrolebinding", ""); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	namespaces, err := c.client.GetNamespaces(ctx, metav1.ListOptions{})
+	if err != nil {
+		if err := p.tolerate(fmt.Errorf("failed to get namespaces for rbac collection: %w", err), "namespace", ""); err != nil {
+			return nil, err
+		}
+	}
+
+	clusterRoleRules := resolveClusterRoles(clusterRoles)
+	for _, clusterRole := range clusterRoles {
+		resources = append(resources, ClusterResource{
+			Kind:    "clusterrole",
+			Group:   "rbac.authorization.k8s.io",
+			Version: "v1",
+			Name:    clusterRole.Name,
+			Data:    clusterRole,
+			Metadata: map[string]string{
+				"aggregated": strconv.FormatBool(clusterRole.AggregationRule != nil),
+			},
+		})
+	}
+
+	var bindings []rbacBinding
+	for _, binding := range clusterRoleBindings {
+		bindings = append(bindings, rbacBinding{name: binding.Name, roleRef: binding.RoleRef, subjects: binding.Subjects})
+		resources = append(resources, ClusterResource{
+			Kind:    "clusterrolebinding",
+			Group:   "rbac.authorization.k8s.io",
+			Version: "v1",
+			Name:    binding.Name,
+			Data:    binding,
+			Metadata: map[string]string{
+				"role": binding.RoleRef.Name,
+			},
+		})
+	}
+
+	namespacedRBAC, err := forEach(ctx, namespaces, c.collectNamespace)
+	if err := p.add(nil, err); err != nil {
+		return nil, err
+	}
+
+	roleRules := make(map[string][]rbacv1.PolicyRule)
+	var serviceAccounts []corev1.ServiceAccount
+	for i, namespace := range namespaces {
+		for _, role := range namespacedRBAC[i].roles {
+			roleRules[role.Namespace+"/"+role.Name] = role.Rules
+			resources = append(resources, ClusterResource{
+				Kind:    "role",
+				Group:   "rbac.authorization.k8s.io",
+				Version: "v1",
+				Name:    role.Name,
+				Data:    role,
+				Metadata: map[string]string{
+					"namespace": namespace.Name,
+				},
+			})
+		}
+
+		for _, binding := range namespacedRBAC[i].roleBindings {
+			bindings = append(bindings, rbacBinding{name: binding.Name, namespace: namespace.Name, roleRef: binding.RoleRef, subjects: binding.Subjects})
+			resources = append(resources, ClusterResource{
+				Kind:    "rolebinding",
+				Group:   "rbac.authorization.k8s.io",
+				Version: "v1",
+				Name:    binding.Name,
+				Data:    binding,
+				Metadata: map[string]string{
+					"namespace": namespace.Name,
+					"role":      binding.RoleRef.Name,
+				},
+			})
+		}
+
+		serviceAccounts = append(serviceAccounts, namespacedRBAC[i].serviceAccounts...)
+	}
+
+	for _, serviceAccount := range serviceAccounts {
+		resources = append(resources, ClusterResource{
+			Kind:    "serviceaccount",
+			Version: "v1",
+			Name:    serviceAccount.Name,
+			Data:    serviceAccount,
+			Metadata: map[string]string{
+				"namespace": serviceAccount.Namespace,
+			},
+		})
+
+		permissions := serviceAccountPermissions(serviceAccount, bindings, roleRules, clusterRoleRules)
+		resources = append(resources, ClusterResource{
+			Kind: "serviceaccountpermissions",
+			Name: serviceAccount.Name,
+			Data: permissions,
+			Metadata: map[string]string{
+				"namespace": serviceAccount.Namespace,
+				"rules":     strconv.Itoa(len(permissions.Rules)),
+			},
+		})
+	}
+
+	p.resources = resources
+	return p.result()
+}
+
+// serviceAccountPermissions collects the rules of every binding that has the
+// service account as one of its subjects
+func serviceAccountPermissions(serviceAccount corev1.ServiceAccount, bindings []rbacBinding, roleRules map[string][]rbacv1.PolicyRule, clusterRoleRules map[string][]rbacv1.PolicyRule) ServiceAccountPermissions {
+	permissions := ServiceAccountPermissions{
+		Namespace:      serviceAccount.Namespace,
+		ServiceAccount: serviceAccount.Name,
+		Rules:          []GrantedRule{},
+	}
+
+	for _, binding := range bindings {
+		subject, matches := matchServiceAccountSubject(serviceAccount, binding)
+		if !matches {
+			continue
+		}
+
+		bindingName := "ClusterRoleBinding/" + binding.name
+		if binding.namespace != "" {
+			bindingName = fmt.Sprintf("RoleBinding/%s/%s", binding.namespace, binding.name)
+		}
+
+		var rules []rbacv1.PolicyRule
+		roleName := fmt.Sprintf("%s/%s", binding.roleRef.Kind, binding.roleRef.Name)
+		switch binding.roleRef.Kind {
+		case "ClusterRole":
+			rules = clusterRoleRules[binding.roleRef.Name]
+		case "Role":
+			rules = roleRules[binding.namespace+"/"+binding.roleRef.Name]
+		}
+
+		for _, rule := range rules {
+			permissions.Rules = append(permissions.Rules, GrantedRule{
+				Namespace:  binding.namespace,
+				Binding:    bindingName,
+				Role:       roleName,
+				Subject:    subject,
+				PolicyRule: rule,
+			})
+		}
+	}
+
+	return permissions
+}
+
+// matchServiceAccountSubject returns the subject of a binding that refers to
+// the service account, either by name or by one of its implicit groups
+func matchServiceAccountSubject(serviceAccount corev1.ServiceAccount, binding rbacBinding) (string, bool) {
+	userName := fmt.Sprintf("system:serviceaccount:%s:%s", serviceAccount.Namespace, serviceAccount.Name)
+	groups := map[string]bool{
+		"system:serviceaccounts":                             true,
+		"system:serviceaccounts:" + serviceAccount.Namespace: true,
+		"system:authenticated":                               true,
+	}
+
+	for _, subject := range binding.subjects {
+		switch subject.Kind {
+		case rbacv1.ServiceAccountKind:
+			namespace := subject.Namespace
+			if namespace == "" {
+				namespace = binding.namespace
+			}
+			if namespace == serviceAccount.Namespace && subject.Name == serviceAccount.Name {
+				return fmt.Sprintf("ServiceAccount/%s/%s", namespace, subject.Name), true
+			}
+		case rbacv1.UserKind:
+			if subject.Name == userName {
+				return "User/" + subject.Name, true
+			}
+		case rbacv1.GroupKind:
+			if groups[subject.Name] {
+				return "Group/" + subject.Name, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// resolveClusterRoles returns the rules of every cluster role with the rules
+// of aggregated cluster roles resolved from their label selectors
+func resolveClusterRoles(clusterRoles []rbacv1.ClusterRole) map[string][]rbacv1.PolicyRule {
+	resolved := make(map[string][]rbacv1.PolicyRule)
+
+	var resolve func(clusterRole rbacv1.ClusterRole, visiting map[string]bool) []rbacv1.PolicyRule
+	resolve = func(clusterRole rbacv1.ClusterRole, visiting map[string]bool) []rbacv1.PolicyRule {
+		if rules, exists := resolved[clusterRole.Name]; exists {
+			return rules
+		}
+
+		rules := append([]rbacv1.PolicyRule{}, clusterRole.Rules...)
+		if clusterRole.AggregationRule != nil && !visiting[clusterRole.Name] {
+			visiting[clusterRole.Name] = true
+			for _, selector := range clusterRole.AggregationRule.ClusterRoleSelectors {
+				labelSelector, err := metav1.LabelSelectorAsSelector(&selector)
+				if err != nil {
+					continue
+				}
+
+				for _, candidate := range clusterRoles {
+					if candidate.Name == clusterRole.Name || !labelSelector.Matches(labels.Set(candidate.Labels)) {
+						continue
+					}
+					for _, rule := range resolve(candidate, visiting) {
+						rules = appendUniqueRule(rules, rule)
+					}
+				}
+			}
+			delete(visiting, clusterRole.Name)
+		}
+
+		// Roles resolved inside a cycle miss the rules of the roles still
+		// being resolved, only complete results are kept
+		if len(visiting) == 0 {
+			resolved[clusterRole.Name] = rules
+		}
+		return rules
+	}
+
+	for _, clusterRole := range clusterRoles {
+		resolve(clusterRole, map[string]bool{})
+	}
+
+	return resolved
+}
+
+// appendUniqueRule appends a rule unless it is already present, the
+// aggregation controller usually copied the rules into the role already
+func appendUniqueRule(rules []rbacv1.PolicyRule, rule rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	for _, existing := range rules {
+		if reflect.DeepEqual(existing, rule) {
+			return rules
+		}
+	}
+	return append(rules, rule)
+}
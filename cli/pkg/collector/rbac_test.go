@@ -0,0 +1,181 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRBACCollector_Name(t *testing.T) {
+	mockClient := &kube.MockClient{}
+	collector := NewRBACCollector(mockClient, KindFilter{})
+
+	assert.Equal(t, "rbac", collector.Name())
+}
+
+func TestRBACCollector_Collect_Permissions(t *testing.T) {
+	readPods := rbacv1.PolicyRule{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"pods"}}
+	readSecrets := rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"secrets"}}
+	discovery := rbacv1.PolicyRule{Verbs: []string{"get"}, NonResourceURLs: []string{"/api"}}
+
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		GetServiceAccountsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.ServiceAccount, error) {
+			return []corev1.ServiceAccount{
+				{ObjectMeta: metav1.ObjectMeta{Name: "controller", Namespace: namespace}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: namespace}},
+			}, nil
+		},
+		GetRolesFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]rbacv1.Role, error) {
+			return []rbacv1.Role{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "secret-reader", Namespace: namespace},
+					Rules:      []rbacv1.PolicyRule{readSecrets},
+				},
+			}, nil
+		},
+		GetRoleBindingsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]rbacv1.RoleBinding, error) {
+			return []rbacv1.RoleBinding{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "controller-secrets", Namespace: namespace},
+					RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "secret-reader"},
+					// Service account subjects default to the binding namespace
+					Subjects: []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "controller"}},
+				},
+			}, nil
+		},
+		GetClusterRolesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]rbacv1.ClusterRole, error) {
+			return []rbacv1.ClusterRole{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "pod-reader", Labels: map[string]string{"aggregate-to-view": "true"}},
+					Rules:      []rbacv1.PolicyRule{readPods},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "view"},
+					AggregationRule: &rbacv1.AggregationRule{
+						ClusterRoleSelectors: []metav1.LabelSelector{
+							{MatchLabels: map[string]string{"aggregate-to-view": "true"}},
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "system:discovery"},
+					Rules:      []rbacv1.PolicyRule{discovery},
+				},
+			}, nil
+		},
+		GetClusterRoleBindingsFunc: func(ctx context.Context, opts metav1.ListOptions) ([]rbacv1.ClusterRoleBinding, error) {
+			return []rbacv1.ClusterRoleBinding{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "controller-view"},
+					RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "view"},
+					Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "controller", Namespace: "default"}},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "system:discovery"},
+					RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "system:discovery"},
+					Subjects:   []rbacv1.Subject{{Kind: rbacv1.GroupKind, Name: "system:authenticated"}},
+				},
+			}, nil
+		},
+	}
+
+	collector := NewRBACCollector(mockClient, KindFilter{})
+	resources, err := collector.Collect(context.Background())
+
+	assert.NoError(t, err)
+
+	permissions := make(map[string]ServiceAccountPermissions)
+	counts := make(map[string]int)
+	for _, resource := range resources {
+		counts[resource.Kind]++
+		if resource.Kind == "serviceaccountpermissions" {
+			permissions[resource.Name] = resource.Data.(ServiceAccountPermissions)
+			assert.Equal(t, "default", resource.Metadata["namespace"])
+		}
+	}
+
+	assert.Equal(t, 3, counts["clusterrole"])
+	assert.Equal(t, 2, counts["clusterrolebinding"])
+	assert.Equal(t, 1, counts["role"])
+	assert.Equal(t, 1, counts["rolebinding"])
+	assert.Equal(t, 2, counts["serviceaccount"])
+	assert.Equal(t, 2, counts["serviceaccountpermissions"])
+
+	controller := permissions["controller"]
+	assert.Len(t, controller.Rules, 3)
+	assert.Contains(t, controller.Rules, GrantedRule{
+		Binding:    "ClusterRoleBinding/controller-view",
+		Role:       "ClusterRole/view",
+		Subject:    "ServiceAccount/default/controller",
+		PolicyRule: readPods,
+	})
+	assert.Contains(t, controller.Rules, GrantedRule{
+		Namespace:  "default",
+		Binding:    "RoleBinding/default/controller-secrets",
+		Role:       "Role/secret-reader",
+		Subject:    "ServiceAccount/default/controller",
+		PolicyRule: readSecrets,
+	})
+
+	defaultAccount := permissions["default"]
+	assert.Len(t, defaultAccount.Rules, 1)
+	assert.Equal(t, "Group/system:authenticated", defaultAccount.Rules[0].Subject)
+}
+
+func TestResolveClusterRoles_AggregationCycle(t *testing.T) {
+	readPods := rbacv1.PolicyRule{Verbs: []string{"get"}, Resources: []string{"pods"}}
+	readNodes := rbacv1.PolicyRule{Verbs: []string{"get"}, Resources: []string{"nodes"}}
+	readEvents := rbacv1.PolicyRule{Verbs: []string{"get"}, Resources: []string{"events"}}
+	aggregate := func(label string) *rbacv1.AggregationRule {
+		return &rbacv1.AggregationRule{ClusterRoleSelectors: []metav1.LabelSelector{{MatchLabels: map[string]string{label: "true"}}}}
+	}
+
+	// monitoring and ops aggregate each other, monitoring also aggregates
+	// events-reader
+	clusterRoles := []rbacv1.ClusterRole{
+		{
+			ObjectMeta:      metav1.ObjectMeta{Name: "monitoring", Labels: map[string]string{"aggregate-to-ops": "true"}},
+			AggregationRule: aggregate("aggregate-to-monitoring"),
+			Rules:           []rbacv1.PolicyRule{readPods},
+		},
+		{
+			ObjectMeta:      metav1.ObjectMeta{Name: "ops", Labels: map[string]string{"aggregate-to-monitoring": "true"}},
+			AggregationRule: aggregate("aggregate-to-ops"),
+			Rules:           []rbacv1.PolicyRule{readNodes},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "events-reader", Labels: map[string]string{"aggregate-to-monitoring": "true"}},
+			Rules:      []rbacv1.PolicyRule{readEvents},
+		},
+	}
+
+	resolved := resolveClusterRoles(clusterRoles)
+
+	assert.ElementsMatch(t, []rbacv1.PolicyRule{readPods, readNodes, readEvents}, resolved["monitoring"])
+	assert.ElementsMatch(t, []rbacv1.PolicyRule{readPods, readNodes, readEvents}, resolved["ops"])
+	assert.ElementsMatch(t, []rbacv1.PolicyRule{readEvents}, resolved["events-reader"])
+}
+
+func TestRBACCollector_Collect_ClusterRoleError(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetClusterRolesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]rbacv1.ClusterRole, error) {
+			return nil, errors.New("failed to get cluster roles")
+		},
+	}
+
+	collector := NewRBACCollector(mockClient, KindFilter{})
+	resources, err := collector.Collect(context.Background())
+
+	assert.Error(t, err)
+	assert.Nil(t, resources)
+	assert.Contains(t, err.Error(), "failed to get cluster roles")
+}
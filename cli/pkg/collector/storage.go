@@ -0,0 +1,236 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StorageCollector collects persistent volumes, their claims and the storage
+// drivers provisioning and attaching them
+type StorageCollector struct {
+	client kube.Client
+	kinds  KindFilter
+}
+
+func NewStorageCollector(client kube.Client, kinds KindFilter) *StorageCollector {
+	return &StorageCollector{client: client, kinds: kinds}
+}
+
+func (c *StorageCollector) Name() string {
+	return "storage"
+}
+
+func (c *StorageCollector) Kinds() []string {
+	return []string{"persistentvolumeclaim", "persistentvolume", "storageclass", "volumeattachment", "csidriver", "csinode"}
+}
+
+func (c *StorageCollector) Collect(ctx context.Context) ([]ClusterResource, error) {
+	collectFuncs := map[string]func(ctx context.Context) ([]ClusterResource, error){
+		"persistentvolumeclaim": c.collectPersistentVolumeClaims,
+		"persistentvolume":      c.collectPersistentVolumes,
+		"storageclass":          c.collectStorageClasses,
+		"volumeattachment":      c.collectVolumeAttachments,
+		"csidriver":             c.collectCSIDrivers,
+		"csinode":               c.collectCSINodes,
+	}
+
+	var p partial
+	for _, kind := range c.Kinds() {
+		if !c.kinds.Matches(c.Name(), kind) {
+			continue
+		}
+		if err := p.add(collectFuncs[kind](ctx)); err != nil {
+			return nil, err
+		}
+	}
+
+	return p.result()
+}
+
+// collectPersistentVolumeClaims records the volume each claim is bound to so
+// that pod -> claim -> volume chains can be followed
+func (c *StorageCollector) collectPersistentVolumeClaims(ctx context.Context) ([]ClusterResource, error) {
+	namespaces, err := c.client.GetNamespaces(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get namespaces for persistentvolumeclaim collection: %w", err), "namespace", "")
+	}
+
+	return collectEach(ctx, namespaces, c.collectNamespaceClaims)
+}
+
+func (c *StorageCollector) collectNamespaceClaims(ctx context.Context, namespace corev1.Namespace) ([]ClusterResource, error) {
+	var resources []ClusterResource
+
+	claims, err := c.client.GetPersistentVolumeClaims(ctx, namespace.Name, metav1.ListOptions{})
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get persistentvolumeclaims from namespace %s: %w", namespace.Name, err), "persistentvolumeclaim", namespace.Name)
+	}
+
+	for _, claim := range claims {
+		metadata := map[string]string{
+			"namespace":    claim.Namespace,
+			"phase":        string(claim.Status.Phase),
+			"volumeName":   claim.Spec.VolumeName,
+			"storageClass": "",
+		}
+		if claim.Spec.StorageClassName != nil {
+			metadata["storageClass"] = *claim.Spec.StorageClassName
+		}
+
+		resources = append(resources, ClusterResource{
+			Kind:     "persistentvolumeclaim",
+			Version:  "v1",
+			Name:     claim.Name,
+			Data:     claim,
+			Metadata: metadata,
+		})
+	}
+
+	return resources, nil
+}
+
+func (c *StorageCollector) collectPersistentVolumes(ctx context.Context) ([]ClusterResource, error) {
+	var resources []ClusterResource
+
+	volumes, err := c.client.GetPersistentVolumes(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get persistentvolumes: %w", err), "persistentvolume", "")
+	}
+
+	for _, volume := range volumes {
+		metadata := map[string]string{
+			"phase":         string(volume.Status.Phase),
+			"storageClass":  volume.Spec.StorageClassName,
+			"reclaimPolicy": string(volume.Spec.PersistentVolumeReclaimPolicy),
+		}
+		if volume.Spec.ClaimRef != nil {
+			metadata["claimNamespace"] = volume.Spec.ClaimRef.Namespace
+			metadata["claimName"] = volume.Spec.ClaimRef.Name
+		}
+		if volume.Spec.CSI != nil {
+			metadata["driver"] = volume.Spec.CSI.Driver
+		}
+
+		resources = append(resources, ClusterResource{
+			Kind:     "persistentvolume",
+			Version:  "v1",
+			Name:     volume.Name,
+			Data:     volume,
+			Metadata: metadata,
+		})
+	}
+
+	return resources, nil
+}
+
+func (c *StorageCollector) collectStorageClasses(ctx context.Context) ([]ClusterResource, error) {
+	var resources []ClusterResource
+
+	storageClasses, err := c.client.GetStorageClasses(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get storageclasses: %w", err), "storageclass", "")
+	}
+
+	for _, storageClass := range storageClasses {
+		metadata := map[string]string{
+			"provisioner": storageClass.Provisioner,
+			"default":     strconv.FormatBool(storageClass.Annotations["storageclass.kubernetes.io/is-default-class"] == "true"),
+		}
+		if storageClass.VolumeBindingMode != nil {
+			metadata["volumeBindingMode"] = string(*storageClass.VolumeBindingMode)
+		}
+
+		resources = append(resources, ClusterResource{
+			Kind:     "storageclass",
+			Group:    "storage.k8s.io",
+			Version:  "v1",
+			Name:     storageClass.Name,
+			Data:     storageClass,
+			Metadata: metadata,
+		})
+	}
+
+	return resources, nil
+}
+
+func (c *StorageCollector) collectVolumeAttachments(ctx context.Context) ([]ClusterResource, error) {
+	var resources []ClusterResource
+
+	attachments, err := c.client.GetVolumeAttachments(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get volumeattachments: %w", err), "volumeattachment", "")
+	}
+
+	for _, attachment := range attachments {
+		metadata := map[string]string{
+			"node":     attachment.Spec.NodeName,
+			"attacher": attachment.Spec.Attacher,
+			"attached": strconv.FormatBool(attachment.Status.Attached),
+		}
+		if attachment.Spec.Source.PersistentVolumeName != nil {
+			metadata["volumeName"] = *attachment.Spec.Source.PersistentVolumeName
+		}
+
+		resources = append(resources, ClusterResource{
+			Kind:     "volumeattachment",
+			Group:    "storage.k8s.io",
+			Version:  "v1",
+			Name:     attachment.Name,
+			Data:     attachment,
+			Metadata: metadata,
+		})
+	}
+
+	return resources, nil
+}
+
+func (c *StorageCollector) collectCSIDrivers(ctx context.Context) ([]ClusterResource, error) {
+	var resources []ClusterResource
+
+	drivers, err := c.client.GetCSIDrivers(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get csidrivers: %w", err), "csidriver", "")
+	}
+
+	for _, driver := range drivers {
+		resources = append(resources, ClusterResource{
+			Kind:     "csidriver",
+			Group:    "storage.k8s.io",
+			Version:  "v1",
+			Name:     driver.Name,
+			Data:     driver,
+			Metadata: nil,
+		})
+	}
+
+	return resources, nil
+}
+
+func (c *StorageCollector) collectCSINodes(ctx context.Context) ([]ClusterResource, error) {
+	var resources []ClusterResource
+
+	nodes, err := c.client.GetCSINodes(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get csinodes: %w", err), "csinode", "")
+	}
+
+	for _, node := range nodes {
+		resources = append(resources, ClusterResource{
+			Kind:    "csinode",
+			Group:   "storage.k8s.io",
+			Version: "v1",
+			Name:    node.Name,
+			Data:    node,
+			Metadata: map[string]string{
+				"drivers": strconv.Itoa(len(node.Spec.Drivers)),
+			},
+		})
+	}
+
+	return resources, nil
+}
@@ -0,0 +1,118 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStorageCollector_Name(t *testing.T) {
+	mockClient := &kube.MockClient{}
+	collector := NewStorageCollector(mockClient, KindFilter{})
+
+	assert.Equal(t, "storage", collector.Name())
+}
+
+func TestStorageCollector_Collect_BindingChain(t *testing.T) {
+	storageClass := "standard"
+	volumeName := "pv-data"
+
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		GetPersistentVolumeClaimsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.PersistentVolumeClaim, error) {
+			return []corev1.PersistentVolumeClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: namespace},
+					Spec: corev1.PersistentVolumeClaimSpec{
+						StorageClassName: &storageClass,
+						VolumeName:       "pv-data",
+					},
+					Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: namespace},
+					Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+				},
+			}, nil
+		},
+		GetPersistentVolumesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.PersistentVolume, error) {
+			return []corev1.PersistentVolume{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "pv-data"},
+					Spec: corev1.PersistentVolumeSpec{
+						StorageClassName: "standard",
+						ClaimRef:         &corev1.ObjectReference{Namespace: "default", Name: "data"},
+					},
+					Status: corev1.PersistentVolumeStatus{Phase: corev1.VolumeBound},
+				},
+			}, nil
+		},
+		GetVolumeAttachmentsFunc: func(ctx context.Context, opts metav1.ListOptions) ([]storagev1.VolumeAttachment, error) {
+			return []storagev1.VolumeAttachment{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "csi-123"},
+					Spec: storagev1.VolumeAttachmentSpec{
+						NodeName: "node-1",
+						Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: &volumeName},
+					},
+					Status: storagev1.VolumeAttachmentStatus{Attached: true},
+				},
+			}, nil
+		},
+	}
+
+	collector := NewStorageCollector(mockClient, KindFilter{Include: []string{"persistentvolume*", "volumeattachment"}})
+	resources, err := collector.Collect(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, resources, 4)
+
+	byName := make(map[string]ClusterResource)
+	for _, resource := range resources {
+		byName[resource.Kind+"/"+resource.Name] = resource
+	}
+
+	claim := byName["persistentvolumeclaim/data"]
+	assert.Equal(t, "default", claim.Metadata["namespace"])
+	assert.Equal(t, "Bound", claim.Metadata["phase"])
+	assert.Equal(t, "pv-data", claim.Metadata["volumeName"])
+	assert.Equal(t, "standard", claim.Metadata["storageClass"])
+
+	pending := byName["persistentvolumeclaim/pending"]
+	assert.Equal(t, "Pending", pending.Metadata["phase"])
+	assert.Equal(t, "", pending.Metadata["volumeName"])
+
+	volume := byName["persistentvolume/pv-data"]
+	assert.Equal(t, "default", volume.Metadata["claimNamespace"])
+	assert.Equal(t, "data", volume.Metadata["claimName"])
+	assert.NotContains(t, volume.Metadata, "namespace")
+
+	attachment := byName["volumeattachment/csi-123"]
+	assert.Equal(t, "storage.k8s.io", attachment.Group)
+	assert.Equal(t, "node-1", attachment.Metadata["node"])
+	assert.Equal(t, "pv-data", attachment.Metadata["volumeName"])
+	assert.Equal(t, "true", attachment.Metadata["attached"])
+}
+
+func TestStorageCollector_Collect_StorageClassError(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetStorageClassesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]storagev1.StorageClass, error) {
+			return nil, errors.New("failed to get storage classes")
+		},
+	}
+
+	collector := NewStorageCollector(mockClient, KindFilter{Include: []string{"storageclass"}})
+	resources, err := collector.Collect(context.Background())
+
+	assert.Error(t, err)
+	assert.Nil(t, resources)
+	assert.Contains(t, err.Error(), "failed to get storage classes")
+}
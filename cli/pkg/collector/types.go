@@ -4,11 +4,18 @@ import "context"
 
 type Collector interface {
 	Name() string
+	// Kinds lists the kinds of the resources the collector produces, nil
+	// when they are only known once the cluster is queried
+	Kinds() []string
 	Collect(ctx context.Context) ([]ClusterResource, error)
 }
 
 type ClusterResource struct {
-	Kind     string
+	Kind string
+	// Group and Version identify the API the resource was read from, an
+	// empty group is the core API
+	Group    string
+	Version  string
 	Name     string
 	Data     interface{}
 	Metadata map[string]string
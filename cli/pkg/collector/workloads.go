@@ -0,0 +1,258 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkloadsCollector collects the apps/v1 and batch/v1 controllers that own
+// pods, along with their rollout status
+type WorkloadsCollector struct {
+	client kube.Client
+	kinds  KindFilter
+}
+
+func NewWorkloadsCollector(client kube.Client, kinds KindFilter) *WorkloadsCollector {
+	return &WorkloadsCollector{client: client, kinds: kinds}
+}
+
+func (c *WorkloadsCollector) Name() string {
+	return "workloads"
+}
+
+func (c *WorkloadsCollector) Kinds() []string {
+	return []string{"deployment", "statefulset", "daemonset", "replicaset", "job", "cronjob"}
+}
+
+func (c *WorkloadsCollector) Collect(ctx context.Context) ([]ClusterResource, error) {
+	namespaces, err := c.client.GetNamespaces(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get namespaces for workload collection: %w", err), "namespace", "")
+	}
+
+	collectFuncs := map[string]func(ctx context.Context, namespace string) ([]ClusterResource, error){
+		"deployment":  c.collectDeployments,
+		"statefulset": c.collectStatefulSets,
+		"daemonset":   c.collectDaemonSets,
+		"replicaset":  c.collectReplicaSets,
+		"job":         c.collectJobs,
+		"cronjob":     c.collectCronJobs,
+	}
+
+	// Kinds that are not captured are not listed at all, some clusters
+	// have huge numbers of old replicasets
+	var selected []func(ctx context.Context, namespace string) ([]ClusterResource, error)
+	for _, kind := range c.Kinds() {
+		if c.kinds.Matches(c.Name(), kind) {
+			selected = append(selected, collectFuncs[kind])
+		}
+	}
+
+	return collectEach(ctx, namespaces, func(ctx context.Context, namespace corev1.Namespace) ([]ClusterResource, error) {
+		var p partial
+		for _, collect := range selected {
+			if err := p.add(collect(ctx, namespace.Name)); err != nil {
+				return nil, err
+			}
+		}
+		return p.result()
+	})
+}
+
+func (c *WorkloadsCollector) collectDeployments(ctx context.Context, namespace string) ([]ClusterResource, error) {
+	var resources []ClusterResource
+
+	deployments, err := c.client.GetDeployments(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get deployments from namespace %s: %w", namespace, err), "deployment", namespace)
+	}
+
+	for _, deployment := range deployments {
+		metadata := workloadMetadata(namespace, deployment.Generation)
+		metadata["observedGeneration"] = strconv.FormatInt(deployment.Status.ObservedGeneration, 10)
+		metadata["replicas"] = strconv.Itoa(int(deployment.Status.Replicas))
+		metadata["readyReplicas"] = strconv.Itoa(int(deployment.Status.ReadyReplicas))
+		metadata["updatedReplicas"] = strconv.Itoa(int(deployment.Status.UpdatedReplicas))
+		metadata["availableReplicas"] = strconv.Itoa(int(deployment.Status.AvailableReplicas))
+		if deployment.Spec.Replicas != nil {
+			metadata["desiredReplicas"] = strconv.Itoa(int(*deployment.Spec.Replicas))
+		}
+
+		resources = append(resources, ClusterResource{
+			Kind:     "deployment",
+			Group:    "apps",
+			Version:  "v1",
+			Name:     deployment.Name,
+			Data:     deployment,
+			Metadata: metadata,
+		})
+	}
+
+	return resources, nil
+}
+
+func (c *WorkloadsCollector) collectStatefulSets(ctx context.Context, namespace string) ([]ClusterResource, error) {
+	var resources []ClusterResource
+
+	statefulSets, err := c.client.GetStatefulSets(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get statefulsets from namespace %s: %w", namespace, err), "statefulset", namespace)
+	}
+
+	for _, statefulSet := range statefulSets {
+		metadata := workloadMetadata(namespace, statefulSet.Generation)
+		metadata["observedGeneration"] = strconv.FormatInt(statefulSet.Status.ObservedGeneration, 10)
+		metadata["replicas"] = strconv.Itoa(int(statefulSet.Status.Replicas))
+		metadata["readyReplicas"] = strconv.Itoa(int(statefulSet.Status.ReadyReplicas))
+		metadata["updatedReplicas"] = strconv.Itoa(int(statefulSet.Status.UpdatedReplicas))
+		metadata["availableReplicas"] = strconv.Itoa(int(statefulSet.Status.AvailableReplicas))
+		if statefulSet.Spec.Replicas != nil {
+			metadata["desiredReplicas"] = strconv.Itoa(int(*statefulSet.Spec.Replicas))
+		}
+
+		resources = append(resources, ClusterResource{
+			Kind:     "statefulset",
+			Group:    "apps",
+			Version:  "v1",
+			Name:     statefulSet.Name,
+			Data:     statefulSet,
+			Metadata: metadata,
+		})
+	}
+
+	return resources, nil
+}
+
+func (c *WorkloadsCollector) collectDaemonSets(ctx context.Context, namespace string) ([]ClusterResource, error) {
+	var resources []ClusterResource
+
+	daemonSets, err := c.client.GetDaemonSets(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get daemonsets from namespace %s: %w", namespace, err), "daemonset", namespace)
+	}
+
+	for _, daemonSet := range daemonSets {
+		metadata := workloadMetadata(namespace, daemonSet.Generation)
+		metadata["observedGeneration"] = strconv.FormatInt(daemonSet.Status.ObservedGeneration, 10)
+		metadata["desiredReplicas"] = strconv.Itoa(int(daemonSet.Status.DesiredNumberScheduled))
+		metadata["readyReplicas"] = strconv.Itoa(int(daemonSet.Status.NumberReady))
+		metadata["updatedReplicas"] = strconv.Itoa(int(daemonSet.Status.UpdatedNumberScheduled))
+		metadata["availableReplicas"] = strconv.Itoa(int(daemonSet.Status.NumberAvailable))
+
+		resources = append(resources, ClusterResource{
+			Kind:     "daemonset",
+			Group:    "apps",
+			Version:  "v1",
+			Name:     daemonSet.Name,
+			Data:     daemonSet,
+			Metadata: metadata,
+		})
+	}
+
+	return resources, nil
+}
+
+func (c *WorkloadsCollector) collectReplicaSets(ctx context.Context, namespace string) ([]ClusterResource, error) {
+	var resources []ClusterResource
+
+	replicaSets, err := c.client.GetReplicaSets(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get replicasets from namespace %s: %w", namespace, err), "replicaset", namespace)
+	}
+
+	for _, replicaSet := range replicaSets {
+		metadata := workloadMetadata(namespace, replicaSet.Generation)
+		metadata["observedGeneration"] = strconv.FormatInt(replicaSet.Status.ObservedGeneration, 10)
+		metadata["replicas"] = strconv.Itoa(int(replicaSet.Status.Replicas))
+		metadata["readyReplicas"] = strconv.Itoa(int(replicaSet.Status.ReadyReplicas))
+		metadata["availableReplicas"] = strconv.Itoa(int(replicaSet.Status.AvailableReplicas))
+		if replicaSet.Spec.Replicas != nil {
+			metadata["desiredReplicas"] = strconv.Itoa(int(*replicaSet.Spec.Replicas))
+		}
+
+		resources = append(resources, ClusterResource{
+			Kind:     "replicaset",
+			Group:    "apps",
+			Version:  "v1",
+			Name:     replicaSet.Name,
+			Data:     replicaSet,
+			Metadata: metadata,
+		})
+	}
+
+	return resources, nil
+}
+
+func (c *WorkloadsCollector) collectJobs(ctx context.Context, namespace string) ([]ClusterResource, error) {
+	var resources []ClusterResource
+
+	jobs, err := c.client.GetJobs(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get jobs from namespace %s: %w", namespace, err), "job", namespace)
+	}
+
+	for _, job := range jobs {
+		metadata := workloadMetadata(namespace, job.Generation)
+		metadata["active"] = strconv.Itoa(int(job.Status.Active))
+		metadata["succeeded"] = strconv.Itoa(int(job.Status.Succeeded))
+		metadata["failed"] = strconv.Itoa(int(job.Status.Failed))
+		if job.Status.Ready != nil {
+			metadata["readyReplicas"] = strconv.Itoa(int(*job.Status.Ready))
+		}
+
+		resources = append(resources, ClusterResource{
+			Kind:     "job",
+			Group:    "batch",
+			Version:  "v1",
+			Name:     job.Name,
+			Data:     job,
+			Metadata: metadata,
+		})
+	}
+
+	return resources, nil
+}
+
+func (c *WorkloadsCollector) collectCronJobs(ctx context.Context, namespace string) ([]ClusterResource, error) {
+	var resources []ClusterResource
+
+	cronJobs, err := c.client.GetCronJobs(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, tolerate(fmt.Errorf("failed to get cronjobs from namespace %s: %w", namespace, err), "cronjob", namespace)
+	}
+
+	for _, cronJob := range cronJobs {
+		metadata := workloadMetadata(namespace, cronJob.Generation)
+		metadata["active"] = strconv.Itoa(len(cronJob.Status.Active))
+		metadata["suspended"] = strconv.FormatBool(cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend)
+		if cronJob.Status.LastScheduleTime != nil {
+			metadata["lastScheduleTime"] = cronJob.Status.LastScheduleTime.UTC().Format(time.RFC3339)
+		}
+
+		resources = append(resources, ClusterResource{
+			Kind:     "cronjob",
+			Group:    "batch",
+			Version:  "v1",
+			Name:     cronJob.Name,
+			Data:     cronJob,
+			Metadata: metadata,
+		})
+	}
+
+	return resources, nil
+}
+
+// workloadMetadata returns the metadata shared by every workload, a rollout is
+// only complete once observedGeneration has caught up with generation
+func workloadMetadata(namespace string, generation int64) map[string]string {
+	return map[string]string{
+		"namespace":  namespace,
+		"generation": strconv.FormatInt(generation, 10),
+	}
+}
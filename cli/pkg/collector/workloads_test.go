@@ -0,0 +1,185 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWorkloadsCollector_Name(t *testing.T) {
+	mockClient := &kube.MockClient{}
+	collector := NewWorkloadsCollector(mockClient, KindFilter{})
+
+	assert.Equal(t, "workloads", collector.Name())
+}
+
+func TestWorkloadsCollector_Collect_Success(t *testing.T) {
+	replicas := int32(3)
+
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		GetDeploymentsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.Deployment, error) {
+			return []appsv1.Deployment{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: namespace, Generation: 4},
+					Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+					Status: appsv1.DeploymentStatus{
+						ObservedGeneration: 3,
+						Replicas:           3,
+						ReadyReplicas:      2,
+						UpdatedReplicas:    1,
+						AvailableReplicas:  2,
+					},
+				},
+			}, nil
+		},
+		GetStatefulSetsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.StatefulSet, error) {
+			return []appsv1.StatefulSet{{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace}}}, nil
+		},
+		GetDaemonSetsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.DaemonSet, error) {
+			return []appsv1.DaemonSet{{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: namespace}}}, nil
+		},
+		GetReplicaSetsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.ReplicaSet, error) {
+			return []appsv1.ReplicaSet{{ObjectMeta: metav1.ObjectMeta{Name: "web-5d8f", Namespace: namespace}}}, nil
+		},
+		GetJobsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]batchv1.Job, error) {
+			return []batchv1.Job{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: namespace},
+					Status:     batchv1.JobStatus{Succeeded: 1},
+				},
+			}, nil
+		},
+		GetCronJobsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]batchv1.CronJob, error) {
+			return []batchv1.CronJob{{ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: namespace}}}, nil
+		},
+	}
+
+	collector := NewWorkloadsCollector(mockClient, KindFilter{})
+	resources, err := collector.Collect(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, resources, 6)
+
+	kinds := make(map[string]ClusterResource)
+	for _, resource := range resources {
+		kinds[resource.Kind] = resource
+		assert.Equal(t, "default", resource.Metadata["namespace"])
+	}
+
+	assert.Equal(t, "apps", kinds["deployment"].Group)
+	assert.Equal(t, "apps", kinds["statefulset"].Group)
+	assert.Equal(t, "apps", kinds["daemonset"].Group)
+	assert.Equal(t, "apps", kinds["replicaset"].Group)
+	assert.Equal(t, "batch", kinds["job"].Group)
+	assert.Equal(t, "batch", kinds["cronjob"].Group)
+
+	deployment := kinds["deployment"]
+	assert.Equal(t, "web", deployment.Name)
+	assert.Equal(t, "4", deployment.Metadata["generation"])
+	assert.Equal(t, "3", deployment.Metadata["observedGeneration"])
+	assert.Equal(t, "3", deployment.Metadata["desiredReplicas"])
+	assert.Equal(t, "2", deployment.Metadata["readyReplicas"])
+	assert.Equal(t, "1", deployment.Metadata["updatedReplicas"])
+	assert.Equal(t, "2", deployment.Metadata["availableReplicas"])
+
+	assert.Equal(t, "1", kinds["job"].Metadata["succeeded"])
+	assert.Equal(t, "false", kinds["cronjob"].Metadata["suspended"])
+}
+
+func TestWorkloadsCollector_Collect_NamespaceError(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return nil, errors.New("failed to connect to cluster")
+		},
+	}
+
+	collector := NewWorkloadsCollector(mockClient, KindFilter{})
+	resources, err := collector.Collect(context.Background())
+
+	assert.Error(t, err)
+	assert.Nil(t, resources)
+	assert.Contains(t, err.Error(), "failed to connect to cluster")
+}
+
+func TestWorkloadsCollector_Collect_DeploymentError(t *testing.T) {
+	// Deployments are listed first, the collection stops at their error
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		GetDeploymentsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.Deployment, error) {
+			return nil, errors.New("failed to get deployments")
+		},
+	}
+
+	collector := NewWorkloadsCollector(mockClient, KindFilter{})
+	resources, err := collector.Collect(context.Background())
+
+	assert.Error(t, err)
+	assert.Nil(t, resources)
+	assert.Contains(t, err.Error(), "namespace default")
+}
+
+func TestWorkloadsCollector_Collect_EmptyNamespace(t *testing.T) {
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		GetDeploymentsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.Deployment, error) {
+			return []appsv1.Deployment{}, nil
+		},
+		GetStatefulSetsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.StatefulSet, error) {
+			return []appsv1.StatefulSet{}, nil
+		},
+		GetDaemonSetsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.DaemonSet, error) {
+			return []appsv1.DaemonSet{}, nil
+		},
+		GetReplicaSetsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.ReplicaSet, error) {
+			return []appsv1.ReplicaSet{}, nil
+		},
+		GetJobsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]batchv1.Job, error) {
+			return []batchv1.Job{}, nil
+		},
+		GetCronJobsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]batchv1.CronJob, error) {
+			return []batchv1.CronJob{}, nil
+		},
+	}
+
+	collector := NewWorkloadsCollector(mockClient, KindFilter{})
+	resources, err := collector.Collect(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, resources, 0)
+}
+
+func TestWorkloadsCollector_Collect_SkipsExcludedKinds(t *testing.T) {
+	listed := false
+	mockClient := &kube.MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}, nil
+		},
+		GetDeploymentsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.Deployment, error) {
+			return []appsv1.Deployment{}, nil
+		},
+		GetReplicaSetsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.ReplicaSet, error) {
+			listed = true
+			return []appsv1.ReplicaSet{}, nil
+		},
+	}
+
+	collector := NewWorkloadsCollector(mockClient, KindFilter{Include: []string{"deployment"}})
+	_, err := collector.Collect(context.Background())
+
+	assert.NoError(t, err)
+	assert.False(t, listed)
+}
@@ -13,6 +13,8 @@ var (
 )
 
 type AppConfig struct {
+	// ConfigFile overrides the location of the config file
+	ConfigFile string `envconfig:"KUBIN_CONFIG"`
 }
 
 func init() {
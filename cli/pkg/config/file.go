@@ -0,0 +1,73 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/3nd3r1/kubin/cli/pkg/redact"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// FileConfig is the content of the kubin config file
+type FileConfig struct {
+	Redaction RedactionConfig `json:"redaction"`
+	Kinds     KindsConfig     `json:"kinds"`
+	Timeouts  TimeoutsConfig  `json:"timeouts"`
+}
+
+type RedactionConfig struct {
+	// Rules are applied to every resource after the built in rules
+	Rules []redact.Rule `json:"rules"`
+	// LogRules are applied to collected logs after the built in detectors
+	LogRules []redact.LogRule `json:"logRules"`
+}
+
+// KindsConfig selects the captured kinds when no kinds are given on the
+// command line
+type KindsConfig struct {
+	Include []string `json:"include"`
+	Exclude []string `json:"exclude"`
+}
+
+// TimeoutsConfig overrides the collector timeout given on the command line
+// for single collectors, keyed by collector name
+type TimeoutsConfig struct {
+	Collectors map[string]metav1.Duration `json:"collectors"`
+}
+
+// LoadFile reads the config file from KUBIN_CONFIG or ~/.kubin/config.yaml,
+// a missing file gives an empty config
+func LoadFile() (*FileConfig, error) {
+	path := Get().ConfigFile
+	if path == "" {
+		dir, err := Dir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(dir, "config.yaml")
+	}
+
+	return loadFile(path)
+}
+
+func loadFile(path string) (*FileConfig, error) {
+	cfg := &FileConfig{}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := yaml.UnmarshalStrict(content, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/3nd3r1/kubin/cli/pkg/redact"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadFile_RedactionRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `
+redaction:
+  rules:
+    - name: database-url
+      kinds: [configmap]
+      jsonPath: $.data.DATABASE_URL
+    - name: internal-hosts
+      regex: '[a-z]+\.corp\.example\.com'
+  logRules:
+    - name: customer-id
+      regex: 'cust_[0-9]{6}'
+`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	cfg, err := loadFile(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []redact.Rule{
+		{Name: "database-url", Kinds: []string{"configmap"}, JSONPath: "$.data.DATABASE_URL"},
+		{Name: "internal-hosts", Regex: `[a-z]+\.corp\.example\.com`},
+	}, cfg.Redaction.Rules)
+	assert.Equal(t, []redact.LogRule{{Name: "customer-id", Regex: "cust_[0-9]{6}"}}, cfg.Redaction.LogRules)
+}
+
+func TestLoadFile_Missing(t *testing.T) {
+	cfg, err := loadFile(filepath.Join(t.TempDir(), "config.yaml"))
+
+	assert.NoError(t, err)
+	assert.Empty(t, cfg.Redaction.Rules)
+}
+
+func TestLoadFile_UnknownField(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("redaction:\n  rule: []\n"), 0600))
+
+	_, err := loadFile(path)
+
+	assert.Error(t, err)
+}
+
+func TestLoadFile_Kinds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `
+kinds:
+  exclude: [lease, event]
+`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	cfg, err := loadFile(path)
+
+	assert.NoError(t, err)
+	assert.Empty(t, cfg.Kinds.Include)
+	assert.Equal(t, []string{"lease", "event"}, cfg.Kinds.Exclude)
+}
+
+func TestLoadFile_Timeouts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `
+timeouts:
+  collectors:
+    metrics: 30s
+    logs: 10m
+`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	cfg, err := loadFile(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, cfg.Timeouts.Collectors["metrics"].Duration)
+	assert.Equal(t, 10*time.Minute, cfg.Timeouts.Collectors["logs"].Duration)
+}
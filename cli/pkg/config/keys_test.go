@@ -0,0 +1,20 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadKey_Stable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kubin", "salt")
+
+	first, err := loadKey(path)
+	assert.NoError(t, err)
+	assert.Len(t, first, keySize)
+
+	second, err := loadKey(path)
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+}
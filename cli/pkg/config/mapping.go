@@ -0,0 +1,79 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// anonymizationFile keeps every anonymized identifier with its original,
+// anonymization is keyed so one file serves every snapshot
+const anonymizationFile = "anonymization.json"
+
+// LoadAnonymizationMapping returns the local mapping of anonymized
+// identifiers to their original values
+func LoadAnonymizationMapping() (map[string]string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	return loadMapping(filepath.Join(dir, anonymizationFile))
+}
+
+// SaveAnonymizationMapping merges mapping into the local mapping file
+func SaveAnonymizationMapping(mapping map[string]string) error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+
+	return saveMapping(filepath.Join(dir, anonymizationFile), mapping)
+}
+
+func loadMapping(path string) (map[string]string, error) {
+	mapping := make(map[string]string)
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return mapping, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read anonymization mapping: %w", err)
+	}
+
+	if err := json.Unmarshal(content, &mapping); err != nil {
+		return nil, fmt.Errorf("failed to parse anonymization mapping %s: %w", path, err)
+	}
+
+	return mapping, nil
+}
+
+func saveMapping(path string, mapping map[string]string) error {
+	merged, err := loadMapping(path)
+	if err != nil {
+		return err
+	}
+
+	for anonymized, original := range mapping {
+		merged[anonymized] = original
+	}
+
+	content, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal anonymization mapping: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		return fmt.Errorf("failed to write anonymization mapping: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveMapping_Merges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "anonymization.json")
+
+	assert.NoError(t, saveMapping(path, map[string]string{"ns-1": "payments"}))
+	assert.NoError(t, saveMapping(path, map[string]string{"pod-2": "api"}))
+
+	mapping, err := loadMapping(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"ns-1": "payments", "pod-2": "api"}, mapping)
+}
@@ -2,75 +2,339 @@ package kube
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
 
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
+	"k8s.io/client-go/util/flowcontrol"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
 )
 
+// Client reads a cluster. Every list method hands opts to the API server,
+// which is how label and field selectors reach it
 type Client interface {
-    GetNamespaces(ctx context.Context) ([]corev1.Namespace, error)
-    GetPods(ctx context.Context, namespace string) ([]corev1.Pod, error)
-    GetPodLogs(ctx context.Context, namespace string, podName string) (string, error)
+	GetNamespaces(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error)
+	GetPods(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error)
+	// GetPodLogs fetches the logs of a single container, opts selects the
+	// container and whether the previous instance is read
+	GetPodLogs(ctx context.Context, namespace string, podName string, opts corev1.PodLogOptions) ([]byte, error)
+
+	GetConfigMaps(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.ConfigMap, error)
+	GetSecrets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Secret, error)
+
+	// GetPreferredResources returns the preferred version of every API
+	// resource served by the cluster, as reported by discovery
+	GetPreferredResources(ctx context.Context) ([]*metav1.APIResourceList, error)
+	// ListResources lists objects of any resource, namespaced tells whether
+	// its objects live in namespaces. An empty namespace lists across all
+	// namespaces
+	ListResources(ctx context.Context, gvr schema.GroupVersionResource, namespaced bool, namespace string, opts metav1.ListOptions) ([]unstructured.Unstructured, error)
+
+	GetDeployments(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.Deployment, error)
+	GetStatefulSets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.StatefulSet, error)
+	GetDaemonSets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.DaemonSet, error)
+	GetReplicaSets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.ReplicaSet, error)
+	GetJobs(ctx context.Context, namespace string, opts metav1.ListOptions) ([]batchv1.Job, error)
+	GetCronJobs(ctx context.Context, namespace string, opts metav1.ListOptions) ([]batchv1.CronJob, error)
+
+	GetServices(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Service, error)
+	GetEndpoints(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Endpoints, error)
+	GetEndpointSlices(ctx context.Context, namespace string, opts metav1.ListOptions) ([]discoveryv1.EndpointSlice, error)
+	GetIngresses(ctx context.Context, namespace string, opts metav1.ListOptions) ([]networkingv1.Ingress, error)
+	GetIngressClasses(ctx context.Context, opts metav1.ListOptions) ([]networkingv1.IngressClass, error)
+	GetNetworkPolicies(ctx context.Context, namespace string, opts metav1.ListOptions) ([]networkingv1.NetworkPolicy, error)
+
+	GetPersistentVolumes(ctx context.Context, opts metav1.ListOptions) ([]corev1.PersistentVolume, error)
+	GetPersistentVolumeClaims(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.PersistentVolumeClaim, error)
+	GetStorageClasses(ctx context.Context, opts metav1.ListOptions) ([]storagev1.StorageClass, error)
+	GetVolumeAttachments(ctx context.Context, opts metav1.ListOptions) ([]storagev1.VolumeAttachment, error)
+	GetCSIDrivers(ctx context.Context, opts metav1.ListOptions) ([]storagev1.CSIDriver, error)
+	GetCSINodes(ctx context.Context, opts metav1.ListOptions) ([]storagev1.CSINode, error)
+
+	GetEvents(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Event, error)
+	GetEventsV1(ctx context.Context, namespace string, opts metav1.ListOptions) ([]eventsv1.Event, error)
+
+	GetNodes(ctx context.Context, opts metav1.ListOptions) ([]corev1.Node, error)
+	// GetNodeStatsSummary fetches the kubelet /stats/summary of a node
+	// through the API server node proxy
+	GetNodeStatsSummary(ctx context.Context, nodeName string) (json.RawMessage, error)
+
+	// GetPodMetrics and GetNodeMetrics read the metrics.k8s.io API, which
+	// is only served when metrics-server or an equivalent is installed
+	GetPodMetrics(ctx context.Context, namespace string, opts metav1.ListOptions) ([]metricsv1beta1.PodMetrics, error)
+	GetNodeMetrics(ctx context.Context, opts metav1.ListOptions) ([]metricsv1beta1.NodeMetrics, error)
+
+	GetServiceAccounts(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.ServiceAccount, error)
+	GetRoles(ctx context.Context, namespace string, opts metav1.ListOptions) ([]rbacv1.Role, error)
+	GetRoleBindings(ctx context.Context, namespace string, opts metav1.ListOptions) ([]rbacv1.RoleBinding, error)
+	GetClusterRoles(ctx context.Context, opts metav1.ListOptions) ([]rbacv1.ClusterRole, error)
+	GetClusterRoleBindings(ctx context.Context, opts metav1.ListOptions) ([]rbacv1.ClusterRoleBinding, error)
+
+	// GetKubeContext returns the kubeconfig context the client talks to
+	GetKubeContext() KubeContext
+	GetServerVersion(ctx context.Context) (*version.Info, error)
+	GetServerGroups(ctx context.Context) (*metav1.APIGroupList, error)
+}
+
+// KubeContext identifies the cluster a client is connected to
+type KubeContext struct {
+	Context string `json:"context"`
+	Cluster string `json:"cluster"`
+	Server  string `json:"server"`
+}
+
+// inClusterContext names the context of a client using the service account
+// of the pod it runs in
+const inClusterContext = "in-cluster"
+
+// ClientOptions controls how a KubeClient talks to the API server
+type ClientOptions struct {
+	// Kubeconfig is read instead of the files listed in $KUBECONFIG or
+	// ~/.kube/config. Without any kubeconfig the in-cluster service
+	// account is used
+	Kubeconfig string
+	// Context is used instead of the current context of the kubeconfig
+	Context string
+	// As and AsGroups impersonate a user and its groups
+	As       string
+	AsGroups []string
+
+	// PageSize is how many objects a list reads per request, zero reads
+	// every list in one request
+	PageSize int64
+	// OnPage is called after every page of a list, it may be called
+	// concurrently
+	OnPage func(PageProgress)
+	// QPS and Burst limit the requests sent to the API server on the
+	// client side, zero uses the client-go defaults of 5 and 10
+	QPS   float32
+	Burst int
 }
 
 type KubeClient struct {
-	clientset *kubernetes.Clientset
+	clientset   *kubernetes.Clientset
+	dynamic     dynamic.Interface
+	metrics     metricsclientset.Interface
+	kubeContext KubeContext
+	pageSize    int64
+	onPage      func(PageProgress)
+	backoff     wait.Backoff
 }
 
 var _ Client = (*KubeClient)(nil)
 
-func NewKubeClient() (*KubeClient, error) {
-    kubeconfigPath := os.Getenv("KUBECONFIG")
-    if kubeconfigPath == "" {
-        if home := homedir.HomeDir(); home != "" {
-            kubeconfigPath = filepath.Join(home, ".kube", "config")
-        } else {
-            return nil, fmt.Errorf("failed to find kubeconfig path")
-        }
-    }
-
-    config, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
-    if err != nil {
-        return nil, err
-    }
+func NewKubeClient(opts ClientOptions) (*KubeClient, error) {
+	if opts.PageSize < 0 {
+		return nil, fmt.Errorf("page size must not be negative, got %d", opts.PageSize)
+	}
+	if opts.QPS < 0 || opts.Burst < 0 {
+		return nil, fmt.Errorf("qps and burst must not be negative")
+	}
+	if len(opts.AsGroups) > 0 && opts.As == "" {
+		return nil, fmt.Errorf("impersonating groups requires a user to impersonate")
+	}
+
+	config, kubeContext, err := loadConfig(opts)
+	if err != nil {
+		return nil, err
+	}
 
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, err
 	}
 
-	return &KubeClient{clientset: clientset}, nil
-}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
 
-func (k *KubeClient) GetNamespaces(ctx context.Context) ([]corev1.Namespace, error) {
-	list, err := k.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	metricsClient, err := metricsclientset.NewForConfig(config)
 	if err != nil {
 		return nil, err
 	}
 
-	return list.Items, nil
+	return &KubeClient{
+		clientset:   clientset,
+		dynamic:     dynamicClient,
+		metrics:     metricsClient,
+		kubeContext: kubeContext,
+		pageSize:    opts.PageSize,
+		onPage:      opts.OnPage,
+		backoff:     defaultBackoff,
+	}, nil
 }
 
-func (k *KubeClient) GetPods(ctx context.Context, namespace string) ([]corev1.Pod, error) {
-	list, err := k.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+// loadConfig resolves the REST config and context the way kubectl does,
+// falling back to the in-cluster service account without a kubeconfig
+func loadConfig(opts ClientOptions) (*rest.Config, KubeContext, error) {
+	// The default rules merge every file listed in $KUBECONFIG
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = opts.Kubeconfig
+
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		loadingRules,
+		&clientcmd.ConfigOverrides{CurrentContext: opts.Context},
+	)
+
+	// Without a kubeconfig this falls back to the in-cluster service account
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, KubeContext{}, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	// Set on the config rather than as an override, overrides are ignored
+	// by the in-cluster config
+	if opts.As != "" {
+		config.Impersonate = rest.ImpersonationConfig{
+			UserName: opts.As,
+			Groups:   opts.AsGroups,
+		}
+	}
+
+	// A single limiter is shared by the typed, dynamic and metrics clients,
+	// so the limits hold for all of their requests together
+	qps, burst := opts.QPS, opts.Burst
+	if qps == 0 {
+		qps = rest.DefaultQPS
+	}
+	if burst == 0 {
+		burst = rest.DefaultBurst
+	}
+	config.RateLimiter = deadlineLimiter{flowcontrol.NewTokenBucketRateLimiter(qps, burst)}
+
+	rawConfig, err := clientConfig.RawConfig()
+	if err != nil {
+		return nil, KubeContext{}, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	kubeContext := KubeContext{
+		Context: rawConfig.CurrentContext,
+		Server:  config.Host,
+	}
+	if opts.Context != "" {
+		kubeContext.Context = opts.Context
+	}
+	if len(rawConfig.Contexts) == 0 {
+		kubeContext.Context = inClusterContext
+	}
+	if current, exists := rawConfig.Contexts[kubeContext.Context]; exists {
+		kubeContext.Cluster = current.Cluster
+	}
+
+	return config, kubeContext, nil
+}
+
+func (k *KubeClient) GetNamespaces(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+	return listPages(ctx, k, "namespaces", "", opts, func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, metav1.ListInterface, error) {
+		list, err := k.clientset.CoreV1().Namespaces().List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetPods(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
+	return listPages(ctx, k, "pods", namespace, opts, func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Pod, metav1.ListInterface, error) {
+		list, err := k.clientset.CoreV1().Pods(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetPodLogs(ctx context.Context, namespace string, podName string, opts corev1.PodLogOptions) ([]byte, error) {
+	var logs []byte
+	err := k.retry(ctx, func() error {
+		var err error
+		logs, err = k.clientset.CoreV1().Pods(namespace).GetLogs(podName, &opts).DoRaw(ctx)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	return list.Items, nil
+
+	return logs, nil
 }
 
-func (k *KubeClient) GetPodLogs(ctx context.Context, namespace string, podName string) (string, error) {
-	req := k.clientset.CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{})
-	logs, err := req.DoRaw(ctx)
+func (k *KubeClient) GetSecrets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Secret, error) {
+	return listPages(ctx, k, "secrets", namespace, opts, func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Secret, metav1.ListInterface, error) {
+		list, err := k.clientset.CoreV1().Secrets(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetPreferredResources(ctx context.Context) ([]*metav1.APIResourceList, error) {
+	var lists []*metav1.APIResourceList
+	err := k.retry(ctx, func() error {
+		var err error
+		lists, err = discovery.ServerPreferredResources(k.clientset.Discovery())
+		return err
+	})
 	if err != nil {
-		return "", err
+		// A broken aggregated API only hides its own group, keep the rest
+		if discovery.IsGroupDiscoveryFailedError(err) && len(lists) > 0 {
+			return lists, nil
+		}
+		return nil, err
 	}
 
-	return string(logs), nil
+	return lists, nil
+}
+
+func (k *KubeClient) ListResources(ctx context.Context, gvr schema.GroupVersionResource, namespaced bool, namespace string, opts metav1.ListOptions) ([]unstructured.Unstructured, error) {
+	return listPages(ctx, k, gvr.Resource, namespace, opts, func(ctx context.Context, opts metav1.ListOptions) ([]unstructured.Unstructured, metav1.ListInterface, error) {
+		list, err := k.dynamic.Resource(gvr).Namespace(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetKubeContext() KubeContext {
+	return k.kubeContext
+}
+
+func (k *KubeClient) GetServerVersion(ctx context.Context) (*version.Info, error) {
+	var info *version.Info
+	err := k.retry(ctx, func() error {
+		var err error
+		info, err = k.clientset.Discovery().ServerVersion()
+		return err
+	})
+	return info, err
+}
+
+func (k *KubeClient) GetServerGroups(ctx context.Context) (*metav1.APIGroupList, error) {
+	var groups *metav1.APIGroupList
+	err := k.retry(ctx, func() error {
+		var err error
+		groups, err = k.clientset.Discovery().ServerGroups()
+		return err
+	})
+	return groups, err
 }
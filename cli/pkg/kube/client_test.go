@@ -0,0 +1,108 @@
+package kube
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func writeKubeconfig(t *testing.T, name string, cluster string, server string) string {
+	content := `apiVersion: v1
+kind: Config
+current-context: ` + name + `
+clusters:
+- name: ` + cluster + `
+  cluster:
+    server: ` + server + `
+contexts:
+- name: ` + name + `
+  context:
+    cluster: ` + cluster + `
+    user: ` + name + `
+users:
+- name: ` + name + `
+  user:
+    token: secret
+`
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadConfig_MergesKubeconfigList(t *testing.T) {
+	dev := writeKubeconfig(t, "dev", "dev-cluster", "https://dev.example.com")
+	prod := writeKubeconfig(t, "prod", "prod-cluster", "https://prod.example.com")
+	t.Setenv("KUBECONFIG", dev+string(os.PathListSeparator)+prod)
+
+	config, kubeContext, err := loadConfig(ClientOptions{Context: "prod"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://prod.example.com", config.Host)
+	assert.Equal(t, KubeContext{Context: "prod", Cluster: "prod-cluster", Server: "https://prod.example.com"}, kubeContext)
+}
+
+func TestLoadConfig_ExplicitKubeconfig(t *testing.T) {
+	t.Setenv("KUBECONFIG", writeKubeconfig(t, "dev", "dev-cluster", "https://dev.example.com"))
+	prod := writeKubeconfig(t, "prod", "prod-cluster", "https://prod.example.com")
+
+	_, kubeContext, err := loadConfig(ClientOptions{Kubeconfig: prod})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "prod", kubeContext.Context)
+}
+
+func TestLoadConfig_UnknownContext(t *testing.T) {
+	t.Setenv("KUBECONFIG", writeKubeconfig(t, "dev", "dev-cluster", "https://dev.example.com"))
+
+	_, _, err := loadConfig(ClientOptions{Context: "staging"})
+
+	assert.ErrorContains(t, err, "failed to load kubeconfig")
+}
+
+func TestLoadConfig_Impersonation(t *testing.T) {
+	t.Setenv("KUBECONFIG", writeKubeconfig(t, "dev", "dev-cluster", "https://dev.example.com"))
+
+	config, _, err := loadConfig(ClientOptions{As: "auditor", AsGroups: []string{"auditors"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "auditor", config.Impersonate.UserName)
+	assert.Equal(t, []string{"auditors"}, config.Impersonate.Groups)
+}
+
+func TestNewKubeClient_GroupsRequireUser(t *testing.T) {
+	_, err := NewKubeClient(ClientOptions{AsGroups: []string{"auditors"}})
+
+	assert.ErrorContains(t, err, "requires a user")
+}
+
+func TestKubeClient_RateLimitedPastDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"kind":"NamespaceList","apiVersion":"v1","metadata":{},"items":[]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewKubeClient(ClientOptions{
+		Kubeconfig: writeKubeconfig(t, "dev", "dev-cluster", server.URL),
+		QPS:        0.1,
+		Burst:      1,
+	})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err = client.GetNamespaces(ctx, metav1.ListOptions{})
+	assert.NoError(t, err)
+
+	// The second request would have to wait ten seconds for the limiter
+	_, err = client.GetNamespaces(ctx, metav1.ListOptions{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
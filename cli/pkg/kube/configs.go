@@ -0,0 +1,18 @@
+package kube
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (k *KubeClient) GetConfigMaps(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.ConfigMap, error) {
+	return listPages(ctx, k, "configmaps", namespace, opts, func(ctx context.Context, opts metav1.ListOptions) ([]corev1.ConfigMap, metav1.ListInterface, error) {
+		list, err := k.clientset.CoreV1().ConfigMaps(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
@@ -0,0 +1,29 @@
+package kube
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (k *KubeClient) GetEvents(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Event, error) {
+	return listPages(ctx, k, "events", namespace, opts, func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Event, metav1.ListInterface, error) {
+		list, err := k.clientset.CoreV1().Events(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetEventsV1(ctx context.Context, namespace string, opts metav1.ListOptions) ([]eventsv1.Event, error) {
+	return listPages(ctx, k, "events.events.k8s.io", namespace, opts, func(ctx context.Context, opts metav1.ListOptions) ([]eventsv1.Event, metav1.ListInterface, error) {
+		list, err := k.clientset.EventsV1().Events(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
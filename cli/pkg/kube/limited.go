@@ -0,0 +1,355 @@
+package kube
+
+import (
+	"context"
+	"encoding/json"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+// LimitedClient bounds how many calls to the API server are in flight at
+// once, shared by everything collecting through it
+type LimitedClient struct {
+	Client
+	slots chan struct{}
+}
+
+var _ Client = (*LimitedClient)(nil)
+
+// NewLimitedClient allows at most concurrency calls at once
+func NewLimitedClient(client Client, concurrency int) *LimitedClient {
+	return &LimitedClient{
+		Client: client,
+		slots:  make(chan struct{}, concurrency),
+	}
+}
+
+// acquire waits for a free slot, giving up when ctx is done
+func (c *LimitedClient) acquire(ctx context.Context) error {
+	select {
+	case c.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *LimitedClient) release() {
+	<-c.slots
+}
+
+func (c *LimitedClient) GetNamespaces(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetNamespaces(ctx, opts)
+}
+
+func (c *LimitedClient) GetPods(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetPods(ctx, namespace, opts)
+}
+
+func (c *LimitedClient) GetPodLogs(ctx context.Context, namespace string, podName string, opts corev1.PodLogOptions) ([]byte, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetPodLogs(ctx, namespace, podName, opts)
+}
+
+func (c *LimitedClient) GetConfigMaps(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.ConfigMap, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetConfigMaps(ctx, namespace, opts)
+}
+
+func (c *LimitedClient) GetSecrets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Secret, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetSecrets(ctx, namespace, opts)
+}
+
+func (c *LimitedClient) GetPreferredResources(ctx context.Context) ([]*metav1.APIResourceList, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetPreferredResources(ctx)
+}
+
+func (c *LimitedClient) ListResources(ctx context.Context, gvr schema.GroupVersionResource, namespaced bool, namespace string, opts metav1.ListOptions) ([]unstructured.Unstructured, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.ListResources(ctx, gvr, namespaced, namespace, opts)
+}
+
+func (c *LimitedClient) GetDeployments(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.Deployment, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetDeployments(ctx, namespace, opts)
+}
+
+func (c *LimitedClient) GetStatefulSets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.StatefulSet, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetStatefulSets(ctx, namespace, opts)
+}
+
+func (c *LimitedClient) GetDaemonSets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.DaemonSet, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetDaemonSets(ctx, namespace, opts)
+}
+
+func (c *LimitedClient) GetReplicaSets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.ReplicaSet, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetReplicaSets(ctx, namespace, opts)
+}
+
+func (c *LimitedClient) GetJobs(ctx context.Context, namespace string, opts metav1.ListOptions) ([]batchv1.Job, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetJobs(ctx, namespace, opts)
+}
+
+func (c *LimitedClient) GetCronJobs(ctx context.Context, namespace string, opts metav1.ListOptions) ([]batchv1.CronJob, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetCronJobs(ctx, namespace, opts)
+}
+
+func (c *LimitedClient) GetServices(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Service, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetServices(ctx, namespace, opts)
+}
+
+func (c *LimitedClient) GetEndpoints(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Endpoints, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetEndpoints(ctx, namespace, opts)
+}
+
+func (c *LimitedClient) GetEndpointSlices(ctx context.Context, namespace string, opts metav1.ListOptions) ([]discoveryv1.EndpointSlice, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetEndpointSlices(ctx, namespace, opts)
+}
+
+func (c *LimitedClient) GetIngresses(ctx context.Context, namespace string, opts metav1.ListOptions) ([]networkingv1.Ingress, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetIngresses(ctx, namespace, opts)
+}
+
+func (c *LimitedClient) GetIngressClasses(ctx context.Context, opts metav1.ListOptions) ([]networkingv1.IngressClass, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetIngressClasses(ctx, opts)
+}
+
+func (c *LimitedClient) GetNetworkPolicies(ctx context.Context, namespace string, opts metav1.ListOptions) ([]networkingv1.NetworkPolicy, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetNetworkPolicies(ctx, namespace, opts)
+}
+
+func (c *LimitedClient) GetPersistentVolumes(ctx context.Context, opts metav1.ListOptions) ([]corev1.PersistentVolume, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetPersistentVolumes(ctx, opts)
+}
+
+func (c *LimitedClient) GetPersistentVolumeClaims(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.PersistentVolumeClaim, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetPersistentVolumeClaims(ctx, namespace, opts)
+}
+
+func (c *LimitedClient) GetStorageClasses(ctx context.Context, opts metav1.ListOptions) ([]storagev1.StorageClass, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetStorageClasses(ctx, opts)
+}
+
+func (c *LimitedClient) GetVolumeAttachments(ctx context.Context, opts metav1.ListOptions) ([]storagev1.VolumeAttachment, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetVolumeAttachments(ctx, opts)
+}
+
+func (c *LimitedClient) GetCSIDrivers(ctx context.Context, opts metav1.ListOptions) ([]storagev1.CSIDriver, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetCSIDrivers(ctx, opts)
+}
+
+func (c *LimitedClient) GetCSINodes(ctx context.Context, opts metav1.ListOptions) ([]storagev1.CSINode, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetCSINodes(ctx, opts)
+}
+
+func (c *LimitedClient) GetEvents(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Event, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetEvents(ctx, namespace, opts)
+}
+
+func (c *LimitedClient) GetEventsV1(ctx context.Context, namespace string, opts metav1.ListOptions) ([]eventsv1.Event, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetEventsV1(ctx, namespace, opts)
+}
+
+func (c *LimitedClient) GetNodes(ctx context.Context, opts metav1.ListOptions) ([]corev1.Node, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetNodes(ctx, opts)
+}
+
+func (c *LimitedClient) GetNodeStatsSummary(ctx context.Context, nodeName string) (json.RawMessage, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetNodeStatsSummary(ctx, nodeName)
+}
+
+func (c *LimitedClient) GetPodMetrics(ctx context.Context, namespace string, opts metav1.ListOptions) ([]metricsv1beta1.PodMetrics, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetPodMetrics(ctx, namespace, opts)
+}
+
+func (c *LimitedClient) GetNodeMetrics(ctx context.Context, opts metav1.ListOptions) ([]metricsv1beta1.NodeMetrics, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetNodeMetrics(ctx, opts)
+}
+
+func (c *LimitedClient) GetServiceAccounts(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.ServiceAccount, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetServiceAccounts(ctx, namespace, opts)
+}
+
+func (c *LimitedClient) GetRoles(ctx context.Context, namespace string, opts metav1.ListOptions) ([]rbacv1.Role, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetRoles(ctx, namespace, opts)
+}
+
+func (c *LimitedClient) GetRoleBindings(ctx context.Context, namespace string, opts metav1.ListOptions) ([]rbacv1.RoleBinding, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetRoleBindings(ctx, namespace, opts)
+}
+
+func (c *LimitedClient) GetClusterRoles(ctx context.Context, opts metav1.ListOptions) ([]rbacv1.ClusterRole, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetClusterRoles(ctx, opts)
+}
+
+func (c *LimitedClient) GetClusterRoleBindings(ctx context.Context, opts metav1.ListOptions) ([]rbacv1.ClusterRoleBinding, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetClusterRoleBindings(ctx, opts)
+}
+
+func (c *LimitedClient) GetServerVersion(ctx context.Context) (*version.Info, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetServerVersion(ctx)
+}
+
+func (c *LimitedClient) GetServerGroups(ctx context.Context) (*metav1.APIGroupList, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return c.Client.GetServerGroups(ctx)
+}
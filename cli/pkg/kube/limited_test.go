@@ -0,0 +1,65 @@
+package kube
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newSlowMockClient(inFlight *atomic.Int32, peak *atomic.Int32) *MockClient {
+	return &MockClient{
+		GetPodsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				previous := peak.Load()
+				if current <= previous || peak.CompareAndSwap(previous, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return nil, nil
+		},
+	}
+}
+
+func TestLimitedClient_BoundsCallsInFlight(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	client := NewLimitedClient(newSlowMockClient(&inFlight, &peak), 2)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetPods(context.Background(), "default", metav1.ListOptions{})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(2), peak.Load())
+}
+
+func TestLimitedClient_CancelledWhileWaiting(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	client := NewLimitedClient(newSlowMockClient(&inFlight, &peak), 1)
+
+	// Hold the only slot so the next call has to wait
+	assert.NoError(t, client.acquire(context.Background()))
+	defer client.release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.GetPods(ctx, "default", metav1.ListOptions{})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(0), peak.Load())
+}
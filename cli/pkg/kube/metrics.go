@@ -0,0 +1,28 @@
+package kube
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+func (k *KubeClient) GetPodMetrics(ctx context.Context, namespace string, opts metav1.ListOptions) ([]metricsv1beta1.PodMetrics, error) {
+	return listPages(ctx, k, "pods.metrics.k8s.io", namespace, opts, func(ctx context.Context, opts metav1.ListOptions) ([]metricsv1beta1.PodMetrics, metav1.ListInterface, error) {
+		list, err := k.metrics.MetricsV1beta1().PodMetricses(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetNodeMetrics(ctx context.Context, opts metav1.ListOptions) ([]metricsv1beta1.NodeMetrics, error) {
+	return listPages(ctx, k, "nodes.metrics.k8s.io", "", opts, func(ctx context.Context, opts metav1.ListOptions) ([]metricsv1beta1.NodeMetrics, metav1.ListInterface, error) {
+		list, err := k.metrics.MetricsV1beta1().NodeMetricses().List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
@@ -2,26 +2,219 @@ package kube
 
 import (
 	"context"
+	"encoding/json"
 
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 )
 
 type MockClient struct {
-	GetNamespacesFunc func(ctx context.Context) ([]corev1.Namespace, error)
-	GetPodsFunc       func(ctx context.Context, namespace string) ([]corev1.Pod, error)
-	GetPodLogsFunc    func(ctx context.Context, namespace string, podName string) (string, error)
+	GetNamespacesFunc             func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error)
+	GetPodsFunc                   func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error)
+	GetPodLogsFunc                func(ctx context.Context, namespace string, podName string, opts corev1.PodLogOptions) ([]byte, error)
+	GetConfigMapsFunc             func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.ConfigMap, error)
+	GetSecretsFunc                func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Secret, error)
+	GetPreferredResourcesFunc     func(ctx context.Context) ([]*metav1.APIResourceList, error)
+	ListResourcesFunc             func(ctx context.Context, gvr schema.GroupVersionResource, namespaced bool, namespace string, opts metav1.ListOptions) ([]unstructured.Unstructured, error)
+	GetDeploymentsFunc            func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.Deployment, error)
+	GetStatefulSetsFunc           func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.StatefulSet, error)
+	GetDaemonSetsFunc             func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.DaemonSet, error)
+	GetReplicaSetsFunc            func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.ReplicaSet, error)
+	GetJobsFunc                   func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]batchv1.Job, error)
+	GetCronJobsFunc               func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]batchv1.CronJob, error)
+	GetServicesFunc               func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Service, error)
+	GetEndpointsFunc              func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Endpoints, error)
+	GetEndpointSlicesFunc         func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]discoveryv1.EndpointSlice, error)
+	GetIngressesFunc              func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]networkingv1.Ingress, error)
+	GetIngressClassesFunc         func(ctx context.Context, opts metav1.ListOptions) ([]networkingv1.IngressClass, error)
+	GetNetworkPoliciesFunc        func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]networkingv1.NetworkPolicy, error)
+	GetPersistentVolumesFunc      func(ctx context.Context, opts metav1.ListOptions) ([]corev1.PersistentVolume, error)
+	GetPersistentVolumeClaimsFunc func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.PersistentVolumeClaim, error)
+	GetStorageClassesFunc         func(ctx context.Context, opts metav1.ListOptions) ([]storagev1.StorageClass, error)
+	GetVolumeAttachmentsFunc      func(ctx context.Context, opts metav1.ListOptions) ([]storagev1.VolumeAttachment, error)
+	GetCSIDriversFunc             func(ctx context.Context, opts metav1.ListOptions) ([]storagev1.CSIDriver, error)
+	GetCSINodesFunc               func(ctx context.Context, opts metav1.ListOptions) ([]storagev1.CSINode, error)
+	GetEventsFunc                 func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Event, error)
+	GetEventsV1Func               func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]eventsv1.Event, error)
+	GetNodesFunc                  func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Node, error)
+	GetNodeStatsSummaryFunc       func(ctx context.Context, nodeName string) (json.RawMessage, error)
+	GetPodMetricsFunc             func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]metricsv1beta1.PodMetrics, error)
+	GetNodeMetricsFunc            func(ctx context.Context, opts metav1.ListOptions) ([]metricsv1beta1.NodeMetrics, error)
+	GetServiceAccountsFunc        func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.ServiceAccount, error)
+	GetRolesFunc                  func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]rbacv1.Role, error)
+	GetRoleBindingsFunc           func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]rbacv1.RoleBinding, error)
+	GetClusterRolesFunc           func(ctx context.Context, opts metav1.ListOptions) ([]rbacv1.ClusterRole, error)
+	GetClusterRoleBindingsFunc    func(ctx context.Context, opts metav1.ListOptions) ([]rbacv1.ClusterRoleBinding, error)
+	GetKubeContextFunc            func() KubeContext
+	GetServerVersionFunc          func(ctx context.Context) (*version.Info, error)
+	GetServerGroupsFunc           func(ctx context.Context) (*metav1.APIGroupList, error)
 }
 
 var _ Client = (*MockClient)(nil)
 
-func (m *MockClient) GetNamespaces(ctx context.Context) ([]corev1.Namespace, error) {
-	return m.GetNamespacesFunc(ctx)
+func (m *MockClient) GetNamespaces(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+	return m.GetNamespacesFunc(ctx, opts)
 }
 
-func (m *MockClient) GetPods(ctx context.Context, namespace string) ([]corev1.Pod, error) {
-	return m.GetPodsFunc(ctx, namespace)
+func (m *MockClient) GetPods(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
+	return m.GetPodsFunc(ctx, namespace, opts)
 }
 
-func (m *MockClient) GetPodLogs(ctx context.Context, namespace string, podName string) (string, error) {
-	return m.GetPodLogsFunc(ctx, namespace, podName)
+func (m *MockClient) GetPodLogs(ctx context.Context, namespace string, podName string, opts corev1.PodLogOptions) ([]byte, error) {
+	return m.GetPodLogsFunc(ctx, namespace, podName, opts)
+}
+
+func (m *MockClient) GetConfigMaps(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.ConfigMap, error) {
+	return m.GetConfigMapsFunc(ctx, namespace, opts)
+}
+
+func (m *MockClient) GetSecrets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Secret, error) {
+	return m.GetSecretsFunc(ctx, namespace, opts)
+}
+
+func (m *MockClient) GetPreferredResources(ctx context.Context) ([]*metav1.APIResourceList, error) {
+	return m.GetPreferredResourcesFunc(ctx)
+}
+
+func (m *MockClient) ListResources(ctx context.Context, gvr schema.GroupVersionResource, namespaced bool, namespace string, opts metav1.ListOptions) ([]unstructured.Unstructured, error) {
+	return m.ListResourcesFunc(ctx, gvr, namespaced, namespace, opts)
+}
+
+func (m *MockClient) GetDeployments(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.Deployment, error) {
+	return m.GetDeploymentsFunc(ctx, namespace, opts)
+}
+
+func (m *MockClient) GetStatefulSets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.StatefulSet, error) {
+	return m.GetStatefulSetsFunc(ctx, namespace, opts)
+}
+
+func (m *MockClient) GetDaemonSets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.DaemonSet, error) {
+	return m.GetDaemonSetsFunc(ctx, namespace, opts)
+}
+
+func (m *MockClient) GetReplicaSets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.ReplicaSet, error) {
+	return m.GetReplicaSetsFunc(ctx, namespace, opts)
+}
+
+func (m *MockClient) GetJobs(ctx context.Context, namespace string, opts metav1.ListOptions) ([]batchv1.Job, error) {
+	return m.GetJobsFunc(ctx, namespace, opts)
+}
+
+func (m *MockClient) GetCronJobs(ctx context.Context, namespace string, opts metav1.ListOptions) ([]batchv1.CronJob, error) {
+	return m.GetCronJobsFunc(ctx, namespace, opts)
+}
+
+func (m *MockClient) GetServices(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Service, error) {
+	return m.GetServicesFunc(ctx, namespace, opts)
+}
+
+func (m *MockClient) GetEndpoints(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Endpoints, error) {
+	return m.GetEndpointsFunc(ctx, namespace, opts)
+}
+
+func (m *MockClient) GetEndpointSlices(ctx context.Context, namespace string, opts metav1.ListOptions) ([]discoveryv1.EndpointSlice, error) {
+	return m.GetEndpointSlicesFunc(ctx, namespace, opts)
+}
+
+func (m *MockClient) GetIngresses(ctx context.Context, namespace string, opts metav1.ListOptions) ([]networkingv1.Ingress, error) {
+	return m.GetIngressesFunc(ctx, namespace, opts)
+}
+
+func (m *MockClient) GetIngressClasses(ctx context.Context, opts metav1.ListOptions) ([]networkingv1.IngressClass, error) {
+	return m.GetIngressClassesFunc(ctx, opts)
+}
+
+func (m *MockClient) GetNetworkPolicies(ctx context.Context, namespace string, opts metav1.ListOptions) ([]networkingv1.NetworkPolicy, error) {
+	return m.GetNetworkPoliciesFunc(ctx, namespace, opts)
+}
+
+func (m *MockClient) GetPersistentVolumes(ctx context.Context, opts metav1.ListOptions) ([]corev1.PersistentVolume, error) {
+	return m.GetPersistentVolumesFunc(ctx, opts)
+}
+
+func (m *MockClient) GetPersistentVolumeClaims(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.PersistentVolumeClaim, error) {
+	return m.GetPersistentVolumeClaimsFunc(ctx, namespace, opts)
+}
+
+func (m *MockClient) GetStorageClasses(ctx context.Context, opts metav1.ListOptions) ([]storagev1.StorageClass, error) {
+	return m.GetStorageClassesFunc(ctx, opts)
+}
+
+func (m *MockClient) GetVolumeAttachments(ctx context.Context, opts metav1.ListOptions) ([]storagev1.VolumeAttachment, error) {
+	return m.GetVolumeAttachmentsFunc(ctx, opts)
+}
+
+func (m *MockClient) GetCSIDrivers(ctx context.Context, opts metav1.ListOptions) ([]storagev1.CSIDriver, error) {
+	return m.GetCSIDriversFunc(ctx, opts)
+}
+
+func (m *MockClient) GetCSINodes(ctx context.Context, opts metav1.ListOptions) ([]storagev1.CSINode, error) {
+	return m.GetCSINodesFunc(ctx, opts)
+}
+
+func (m *MockClient) GetEvents(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Event, error) {
+	return m.GetEventsFunc(ctx, namespace, opts)
+}
+
+func (m *MockClient) GetEventsV1(ctx context.Context, namespace string, opts metav1.ListOptions) ([]eventsv1.Event, error) {
+	return m.GetEventsV1Func(ctx, namespace, opts)
+}
+
+func (m *MockClient) GetNodes(ctx context.Context, opts metav1.ListOptions) ([]corev1.Node, error) {
+	return m.GetNodesFunc(ctx, opts)
+}
+
+func (m *MockClient) GetNodeStatsSummary(ctx context.Context, nodeName string) (json.RawMessage, error) {
+	return m.GetNodeStatsSummaryFunc(ctx, nodeName)
+}
+
+func (m *MockClient) GetPodMetrics(ctx context.Context, namespace string, opts metav1.ListOptions) ([]metricsv1beta1.PodMetrics, error) {
+	return m.GetPodMetricsFunc(ctx, namespace, opts)
+}
+
+func (m *MockClient) GetNodeMetrics(ctx context.Context, opts metav1.ListOptions) ([]metricsv1beta1.NodeMetrics, error) {
+	return m.GetNodeMetricsFunc(ctx, opts)
+}
+
+func (m *MockClient) GetServiceAccounts(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.ServiceAccount, error) {
+	return m.GetServiceAccountsFunc(ctx, namespace, opts)
+}
+
+func (m *MockClient) GetRoles(ctx context.Context, namespace string, opts metav1.ListOptions) ([]rbacv1.Role, error) {
+	return m.GetRolesFunc(ctx, namespace, opts)
+}
+
+func (m *MockClient) GetRoleBindings(ctx context.Context, namespace string, opts metav1.ListOptions) ([]rbacv1.RoleBinding, error) {
+	return m.GetRoleBindingsFunc(ctx, namespace, opts)
+}
+
+func (m *MockClient) GetClusterRoles(ctx context.Context, opts metav1.ListOptions) ([]rbacv1.ClusterRole, error) {
+	return m.GetClusterRolesFunc(ctx, opts)
+}
+
+func (m *MockClient) GetClusterRoleBindings(ctx context.Context, opts metav1.ListOptions) ([]rbacv1.ClusterRoleBinding, error) {
+	return m.GetClusterRoleBindingsFunc(ctx, opts)
+}
+
+func (m *MockClient) GetKubeContext() KubeContext {
+	return m.GetKubeContextFunc()
+}
+
+func (m *MockClient) GetServerVersion(ctx context.Context) (*version.Info, error) {
+	return m.GetServerVersionFunc(ctx)
+}
+
+func (m *MockClient) GetServerGroups(ctx context.Context) (*metav1.APIGroupList, error) {
+	return m.GetServerGroupsFunc(ctx)
 }
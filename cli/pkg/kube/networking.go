@@ -0,0 +1,70 @@
+package kube
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (k *KubeClient) GetServices(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Service, error) {
+	return listPages(ctx, k, "services", namespace, opts, func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Service, metav1.ListInterface, error) {
+		list, err := k.clientset.CoreV1().Services(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetEndpoints(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Endpoints, error) {
+	return listPages(ctx, k, "endpoints", namespace, opts, func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Endpoints, metav1.ListInterface, error) {
+		list, err := k.clientset.CoreV1().Endpoints(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetEndpointSlices(ctx context.Context, namespace string, opts metav1.ListOptions) ([]discoveryv1.EndpointSlice, error) {
+	return listPages(ctx, k, "endpointslices", namespace, opts, func(ctx context.Context, opts metav1.ListOptions) ([]discoveryv1.EndpointSlice, metav1.ListInterface, error) {
+		list, err := k.clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetIngresses(ctx context.Context, namespace string, opts metav1.ListOptions) ([]networkingv1.Ingress, error) {
+	return listPages(ctx, k, "ingresses", namespace, opts, func(ctx context.Context, opts metav1.ListOptions) ([]networkingv1.Ingress, metav1.ListInterface, error) {
+		list, err := k.clientset.NetworkingV1().Ingresses(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetIngressClasses(ctx context.Context, opts metav1.ListOptions) ([]networkingv1.IngressClass, error) {
+	return listPages(ctx, k, "ingressclasses", "", opts, func(ctx context.Context, opts metav1.ListOptions) ([]networkingv1.IngressClass, metav1.ListInterface, error) {
+		list, err := k.clientset.NetworkingV1().IngressClasses().List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetNetworkPolicies(ctx context.Context, namespace string, opts metav1.ListOptions) ([]networkingv1.NetworkPolicy, error) {
+	return listPages(ctx, k, "networkpolicies", namespace, opts, func(ctx context.Context, opts metav1.ListOptions) ([]networkingv1.NetworkPolicy, metav1.ListInterface, error) {
+		list, err := k.clientset.NetworkingV1().NetworkPolicies(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
@@ -0,0 +1,38 @@
+package kube
+
+import (
+	"context"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (k *KubeClient) GetNodes(ctx context.Context, opts metav1.ListOptions) ([]corev1.Node, error) {
+	return listPages(ctx, k, "nodes", "", opts, func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Node, metav1.ListInterface, error) {
+		list, err := k.clientset.CoreV1().Nodes().List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetNodeStatsSummary(ctx context.Context, nodeName string) (json.RawMessage, error) {
+	var summary []byte
+	err := k.retry(ctx, func() error {
+		var err error
+		summary, err = k.clientset.CoreV1().RESTClient().Get().
+			Resource("nodes").
+			Name(nodeName).
+			SubResource("proxy").
+			Suffix("stats/summary").
+			DoRaw(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
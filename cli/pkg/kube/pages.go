@@ -0,0 +1,86 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultPageSize matches the page size kubectl uses
+const DefaultPageSize = 500
+
+// maxListRestarts bounds how often a list starts over after its continue
+// token expired, on a busy cluster each attempt takes as long as the last
+const maxListRestarts = 3
+
+// PageProgress describes a page of a list that was read
+type PageProgress struct {
+	Resource  string
+	Namespace string
+	Page      int
+	// Items counts the items read so far, all pages included
+	Items int
+	// Remaining is the server's estimate of the items left, nil when the
+	// server does not know
+	Remaining *int64
+}
+
+// pageFunc reads one page of a list
+type pageFunc[T any] func(ctx context.Context, opts metav1.ListOptions) ([]T, metav1.ListInterface, error)
+
+// listPages reads a list page by page. When the continue token expires the
+// list starts over, since stitching pages from different resource versions
+// together would not be a consistent view
+func listPages[T any](ctx context.Context, k *KubeClient, resource string, namespace string, opts metav1.ListOptions, page pageFunc[T]) ([]T, error) {
+	opts.Limit = k.pageSize
+
+	for restarts := 0; ; restarts++ {
+		items, err := readPages(ctx, k, resource, namespace, opts, page)
+		if err == nil {
+			return items, nil
+		}
+
+		if !apierrors.IsResourceExpired(err) || restarts == maxListRestarts {
+			return nil, err
+		}
+	}
+}
+
+func readPages[T any](ctx context.Context, k *KubeClient, resource string, namespace string, opts metav1.ListOptions, page pageFunc[T]) ([]T, error) {
+	var items []T
+
+	opts.Continue = ""
+	for number := 1; ; number++ {
+		var pageItems []T
+		var list metav1.ListInterface
+		err := k.retry(ctx, func() error {
+			var err error
+			pageItems, list, err = page(ctx, opts)
+			return err
+		})
+		if err != nil {
+			if number > 1 {
+				return nil, fmt.Errorf("failed to read page %d of %s: %w", number, resource, err)
+			}
+			return nil, err
+		}
+		items = append(items, pageItems...)
+
+		if k.onPage != nil {
+			k.onPage(PageProgress{
+				Resource:  resource,
+				Namespace: namespace,
+				Page:      number,
+				Items:     len(items),
+				Remaining: list.GetRemainingItemCount(),
+			})
+		}
+
+		opts.Continue = list.GetContinue()
+		if opts.Continue == "" {
+			return items, nil
+		}
+	}
+}
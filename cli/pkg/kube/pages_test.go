@@ -0,0 +1,127 @@
+package kube
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newPodPages serves pods page by page, the continue token is the index of
+// the next pod
+func newPodPages(pods []string, calls *[]metav1.ListOptions) pageFunc[corev1.Pod] {
+	return func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Pod, metav1.ListInterface, error) {
+		*calls = append(*calls, opts)
+
+		start := 0
+		if opts.Continue != "" {
+			start, _ = strconv.Atoi(opts.Continue)
+		}
+		end := min(start+int(opts.Limit), len(pods))
+
+		list := &corev1.PodList{}
+		for _, name := range pods[start:end] {
+			list.Items = append(list.Items, corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		}
+		if end < len(pods) {
+			list.Continue = strconv.Itoa(end)
+			remaining := int64(len(pods) - end)
+			list.RemainingItemCount = &remaining
+		}
+
+		return list.Items, list, nil
+	}
+}
+
+func podNames(pods []corev1.Pod) []string {
+	var names []string
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	return names
+}
+
+func TestListPages_ReadsEveryPage(t *testing.T) {
+	var calls []metav1.ListOptions
+	var progress []PageProgress
+	client := &KubeClient{
+		pageSize: 2,
+		onPage: func(p PageProgress) {
+			progress = append(progress, p)
+		},
+	}
+
+	pods, err := listPages(context.Background(), client, "pods", "default", metav1.ListOptions{LabelSelector: "app=web"}, newPodPages([]string{"a", "b", "c", "d", "e"}, &calls))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, podNames(pods))
+	assert.Len(t, calls, 3)
+	for _, opts := range calls {
+		assert.Equal(t, int64(2), opts.Limit)
+		assert.Equal(t, "app=web", opts.LabelSelector)
+	}
+	assert.Equal(t, []string{"", "2", "4"}, []string{calls[0].Continue, calls[1].Continue, calls[2].Continue})
+
+	assert.Len(t, progress, 3)
+	assert.Equal(t, 2, progress[0].Items)
+	assert.Equal(t, int64(3), *progress[0].Remaining)
+	assert.Equal(t, 3, progress[2].Page)
+	assert.Equal(t, 5, progress[2].Items)
+	assert.Nil(t, progress[2].Remaining)
+}
+
+func TestListPages_RestartsWhenContinueExpires(t *testing.T) {
+	var calls []metav1.ListOptions
+	pages := newPodPages([]string{"a", "b", "c"}, &calls)
+	expired := false
+
+	client := &KubeClient{pageSize: 2}
+	pods, err := listPages(context.Background(), client, "pods", "", metav1.ListOptions{}, func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Pod, metav1.ListInterface, error) {
+		if opts.Continue != "" && !expired {
+			expired = true
+			return nil, nil, apierrors.NewResourceExpired("continue token expired")
+		}
+		return pages(ctx, opts)
+	})
+
+	assert.NoError(t, err)
+	// The first page is not kept twice
+	assert.Equal(t, []string{"a", "b", "c"}, podNames(pods))
+	assert.Equal(t, "", calls[0].Continue)
+	assert.Equal(t, "", calls[1].Continue)
+}
+
+func TestListPages_GivesUpAfterRepeatedExpiry(t *testing.T) {
+	var calls []metav1.ListOptions
+	pages := newPodPages([]string{"a", "b", "c"}, &calls)
+
+	client := &KubeClient{pageSize: 2}
+	_, err := listPages(context.Background(), client, "pods", "", metav1.ListOptions{}, func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Pod, metav1.ListInterface, error) {
+		if opts.Continue != "" {
+			return nil, nil, apierrors.NewResourceExpired("continue token expired")
+		}
+		return pages(ctx, opts)
+	})
+
+	assert.True(t, apierrors.IsResourceExpired(err))
+	assert.Len(t, calls, maxListRestarts+1)
+}
+
+func TestListPages_ReturnsOtherErrors(t *testing.T) {
+	failure := errors.New("connection refused")
+	calls := 0
+
+	client := &KubeClient{}
+	_, err := listPages(context.Background(), client, "pods", "", metav1.ListOptions{}, func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Pod, metav1.ListInterface, error) {
+		calls++
+		return nil, nil, failure
+	})
+
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, 1, calls)
+}
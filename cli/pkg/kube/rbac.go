@@ -0,0 +1,59 @@
+package kube
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (k *KubeClient) GetServiceAccounts(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.ServiceAccount, error) {
+	return listPages(ctx, k, "serviceaccounts", namespace, opts, func(ctx context.Context, opts metav1.ListOptions) ([]corev1.ServiceAccount, metav1.ListInterface, error) {
+		list, err := k.clientset.CoreV1().ServiceAccounts(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetRoles(ctx context.Context, namespace string, opts metav1.ListOptions) ([]rbacv1.Role, error) {
+	return listPages(ctx, k, "roles", namespace, opts, func(ctx context.Context, opts metav1.ListOptions) ([]rbacv1.Role, metav1.ListInterface, error) {
+		list, err := k.clientset.RbacV1().Roles(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetRoleBindings(ctx context.Context, namespace string, opts metav1.ListOptions) ([]rbacv1.RoleBinding, error) {
+	return listPages(ctx, k, "rolebindings", namespace, opts, func(ctx context.Context, opts metav1.ListOptions) ([]rbacv1.RoleBinding, metav1.ListInterface, error) {
+		list, err := k.clientset.RbacV1().RoleBindings(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetClusterRoles(ctx context.Context, opts metav1.ListOptions) ([]rbacv1.ClusterRole, error) {
+	return listPages(ctx, k, "clusterroles", "", opts, func(ctx context.Context, opts metav1.ListOptions) ([]rbacv1.ClusterRole, metav1.ListInterface, error) {
+		list, err := k.clientset.RbacV1().ClusterRoles().List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetClusterRoleBindings(ctx context.Context, opts metav1.ListOptions) ([]rbacv1.ClusterRoleBinding, error) {
+	return listPages(ctx, k, "clusterrolebindings", "", opts, func(ctx context.Context, opts metav1.ListOptions) ([]rbacv1.ClusterRoleBinding, metav1.ListInterface, error) {
+		list, err := k.clientset.RbacV1().ClusterRoleBindings().List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
@@ -0,0 +1,113 @@
+package kube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/3nd3r1/kubin/cli/pkg/log"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// defaultBackoff spaces the retries of a request out to roughly 0.5s, 1s,
+// 2s, 4s and 8s before giving up
+var defaultBackoff = wait.Backoff{
+	Duration: 500 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.5,
+	Steps:    5,
+	Cap:      30 * time.Second,
+}
+
+// retry calls fn again after transient errors with jittered exponential
+// backoff
+func (k *KubeClient) retry(ctx context.Context, fn func() error) error {
+	backoff := k.backoff
+
+	for attempt := 0; ; attempt++ {
+		err := deadlineError(ctx, fn())
+		if err == nil || !isTransient(err) || attempt == k.backoff.Steps {
+			return err
+		}
+
+		delay := backoff.Step()
+		log.Debug("Retrying request", "attempt", attempt+1, "delay", delay, "error", err)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// deadlineError makes a request that was cut short by the deadline of its
+// context say so, not every error of the transport wraps
+// context.DeadlineExceeded
+func deadlineError(ctx context.Context, err error) error {
+	if err == nil || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	// Answers of the API server stand, even when they came in late
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		return err
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", context.DeadlineExceeded, err)
+	}
+
+	return err
+}
+
+// deadlineLimiter is the client side rate limiter of the API clients. The
+// limiter refuses right away to wait for a token that only comes after the
+// deadline of a request, deadlineLimiter turns that refusal into
+// context.DeadlineExceeded so the request counts as timed out
+type deadlineLimiter struct {
+	flowcontrol.RateLimiter
+}
+
+func (l deadlineLimiter) Wait(ctx context.Context) error {
+	err := l.RateLimiter.Wait(ctx)
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+
+	if _, hasDeadline := ctx.Deadline(); hasDeadline {
+		return fmt.Errorf("%w: %w", context.DeadlineExceeded, err)
+	}
+
+	return err
+}
+
+// isTransient reports whether a request may succeed when it is sent again
+func isTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		// client-go already waited as long as the API server asked before
+		// giving up, another round would only keep hammering it
+		if _, ok := apierrors.SuggestsClientDelay(err); ok {
+			return false
+		}
+		// Unavailable aggregated APIs, such as metrics-server that is not
+		// running, do not come back within a few seconds
+		if apierrors.IsServiceUnavailable(err) {
+			return false
+		}
+		// Server errors include etcd timeouts
+		return apierrors.IsTooManyRequests(err) || status.Status().Code >= 500
+	}
+
+	return utilnet.IsConnectionReset(err) || utilnet.IsProbableEOF(err) || utilnet.IsHTTP2ConnectionLost(err)
+}
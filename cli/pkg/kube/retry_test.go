@@ -0,0 +1,161 @@
+package kube
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+func newRetryingClient(steps int) *KubeClient {
+	return &KubeClient{backoff: wait.Backoff{Duration: time.Millisecond, Factor: 2, Jitter: 0.5, Steps: steps}}
+}
+
+func TestIsTransient(t *testing.T) {
+	pods := schema.GroupResource{Resource: "pods"}
+
+	tests := []struct {
+		name      string
+		err       error
+		transient bool
+	}{
+		{name: "too many requests", err: apierrors.NewTooManyRequests("throttled", 0), transient: true},
+		{name: "too many requests with retry after", err: apierrors.NewTooManyRequests("throttled", 1), transient: false},
+		{name: "internal error", err: apierrors.NewInternalError(errors.New("etcdserver: request timed out")), transient: true},
+		{name: "service unavailable", err: apierrors.NewServiceUnavailable("metrics unavailable"), transient: false},
+		{name: "server timeout", err: apierrors.NewServerTimeout(pods, "list", 1), transient: false},
+		{name: "connection reset", err: errors.New("read tcp 10.0.0.1:443: read: connection reset by peer"), transient: true},
+		{name: "unexpected eof", err: io.ErrUnexpectedEOF, transient: true},
+		{name: "forbidden", err: apierrors.NewForbidden(pods, "", errors.New("no access")), transient: false},
+		{name: "not found", err: apierrors.NewNotFound(pods, "web"), transient: false},
+		{name: "deadline", err: context.DeadlineExceeded, transient: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.transient, isTransient(tt.err))
+		})
+	}
+}
+
+func TestRetry_RecoversFromTransientErrors(t *testing.T) {
+	client := newRetryingClient(5)
+	calls := 0
+
+	err := client.retry(context.Background(), func() error {
+		calls++
+		if calls < 3 {
+			return apierrors.NewInternalError(errors.New("etcdserver: leader changed"))
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetry_GivesUp(t *testing.T) {
+	client := newRetryingClient(2)
+	calls := 0
+
+	err := client.retry(context.Background(), func() error {
+		calls++
+		return apierrors.NewInternalError(errors.New("etcdserver: request timed out"))
+	})
+
+	assert.True(t, apierrors.IsInternalError(err))
+	// The first attempt and one per backoff step
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetry_DoesNotRetryPermanentErrors(t *testing.T) {
+	client := newRetryingClient(5)
+	calls := 0
+
+	err := client.retry(context.Background(), func() error {
+		calls++
+		return apierrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "", errors.New("no access"))
+	})
+
+	assert.True(t, apierrors.IsForbidden(err))
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetry_LeavesRetryAfterToClientGo(t *testing.T) {
+	client := newRetryingClient(5)
+	calls := 0
+
+	err := client.retry(context.Background(), func() error {
+		calls++
+		return apierrors.NewTooManyRequests("throttled by priority and fairness", 1)
+	})
+
+	assert.True(t, apierrors.IsTooManyRequests(err))
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetry_DoesNotRetryUnavailableAPIs(t *testing.T) {
+	client := newRetryingClient(5)
+	calls := 0
+
+	err := client.retry(context.Background(), func() error {
+		calls++
+		return apierrors.NewServiceUnavailable("the server is currently unable to handle the request")
+	})
+
+	assert.True(t, apierrors.IsServiceUnavailable(err))
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetry_StopsWhenCancelled(t *testing.T) {
+	client := &KubeClient{backoff: wait.Backoff{Duration: time.Hour, Steps: 5}}
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+
+	err := client.retry(ctx, func() error {
+		calls++
+		cancel()
+		return apierrors.NewInternalError(errors.New("etcdserver: leader changed"))
+	})
+
+	assert.True(t, apierrors.IsInternalError(err))
+	assert.Equal(t, 1, calls)
+}
+
+func TestDeadlineError(t *testing.T) {
+	reset := errors.New("read tcp 10.0.0.1:443: read: connection reset by peer")
+
+	expired, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	assert.ErrorIs(t, deadlineError(expired, reset), context.DeadlineExceeded)
+
+	// Before the deadline, or for answers of the API server, nothing changes
+	assert.Equal(t, reset, deadlineError(context.Background(), reset))
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("no access"))
+	assert.Equal(t, error(forbidden), deadlineError(expired, forbidden))
+}
+
+func TestDeadlineLimiter(t *testing.T) {
+	limiter := deadlineLimiter{flowcontrol.NewTokenBucketRateLimiter(0.1, 1)}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assert.NoError(t, limiter.Wait(ctx))
+
+	// The next token is ten seconds away, the limiter refuses to wait for it
+	start := time.Now()
+	err := limiter.Wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+
+	cancelled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	assert.ErrorIs(t, limiter.Wait(cancelled), context.Canceled)
+}
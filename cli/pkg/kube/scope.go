@@ -0,0 +1,579 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/3nd3r1/kubin/cli/pkg/log"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+// Scope selects the part of a cluster a snapshot covers. Namespaces are
+// matched with glob patterns, an empty include list covers every namespace
+type Scope struct {
+	Namespaces        []string `json:"namespaces,omitempty"`
+	ExcludeNamespaces []string `json:"excludeNamespaces,omitempty"`
+
+	// LabelSelector and FieldSelector are added to every list of namespaced
+	// resources
+	LabelSelector string `json:"labelSelector,omitempty"`
+	FieldSelector string `json:"fieldSelector,omitempty"`
+
+	// IncludeRelated also captures the owners of selected pods, the
+	// services selecting them, the claims they mount and their events
+	IncludeRelated bool `json:"includeRelated,omitempty"`
+}
+
+// Validate checks that every pattern is a valid glob and that the
+// selectors parse
+func (s Scope) Validate() error {
+	for _, pattern := range append(append([]string{}, s.Namespaces...), s.ExcludeNamespaces...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid namespace pattern %q: %w", pattern, err)
+		}
+	}
+
+	if _, err := labels.Parse(s.LabelSelector); err != nil {
+		return fmt.Errorf("invalid label selector %q: %w", s.LabelSelector, err)
+	}
+
+	if _, err := fields.ParseSelector(s.FieldSelector); err != nil {
+		return fmt.Errorf("invalid field selector %q: %w", s.FieldSelector, err)
+	}
+
+	return nil
+}
+
+// IsPartial reports whether the scope leaves out any part of the cluster
+func (s Scope) IsPartial() bool {
+	return len(s.Namespaces) > 0 || len(s.ExcludeNamespaces) > 0 || s.hasSelector()
+}
+
+func (s Scope) hasSelector() bool {
+	return s.LabelSelector != "" || s.FieldSelector != ""
+}
+
+// Includes reports whether a namespace is part of the scope, the empty
+// namespace of cluster scoped objects always is
+func (s Scope) Includes(namespace string) bool {
+	if namespace == "" {
+		return true
+	}
+
+	for _, pattern := range s.ExcludeNamespaces {
+		if matched, _ := path.Match(pattern, namespace); matched {
+			return false
+		}
+	}
+
+	if len(s.Namespaces) == 0 {
+		return true
+	}
+
+	for _, pattern := range s.Namespaces {
+		if matched, _ := path.Match(pattern, namespace); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// namedNamespaces returns the namespaces of the scope when every include
+// pattern is a plain name rather than a glob, sorted like the API server
+// sorts lists across all namespaces
+func (s Scope) namedNamespaces() ([]string, bool) {
+	if len(s.Namespaces) == 0 {
+		return nil, false
+	}
+
+	var names []string
+	for _, pattern := range s.Namespaces {
+		if strings.ContainsAny(pattern, `*?[\`) {
+			return nil, false
+		}
+		if s.Includes(pattern) && !slices.Contains(names, pattern) {
+			names = append(names, pattern)
+		}
+	}
+	sort.Strings(names)
+
+	return names, true
+}
+
+// listOptions adds the selectors of the scope to the options of a list call
+func (s Scope) listOptions(opts metav1.ListOptions) metav1.ListOptions {
+	opts.LabelSelector = joinSelectors(opts.LabelSelector, s.LabelSelector)
+	opts.FieldSelector = joinSelectors(opts.FieldSelector, s.FieldSelector)
+	return opts
+}
+
+func joinSelectors(a string, b string) string {
+	if a == "" {
+		return b
+	}
+	if b == "" {
+		return a
+	}
+	return a + "," + b
+}
+
+// ScopedClient limits every namespaced call of a client to the namespaces
+// and selectors of a scope. Cluster scoped calls, namespaces included, are
+// passed through as they are since they give the selected objects context
+type ScopedClient struct {
+	Client
+	scope Scope
+
+	// warned remembers the types a field selector was rejected for
+	warned sync.Map
+
+	// related is found once and shared by every caller, relatedErr keeps
+	// failures that would happen again
+	relatedMu  sync.Mutex
+	related    map[objectRef]bool
+	relatedErr error
+}
+
+var _ Client = (*ScopedClient)(nil)
+
+func NewScopedClient(client Client, scope Scope) *ScopedClient {
+	return &ScopedClient{Client: client, scope: scope}
+}
+
+// WithoutSelectors returns a client limited to the namespaces of the scope
+// only, for lookups that bring a selector of their own
+func (c *ScopedClient) WithoutSelectors() *ScopedClient {
+	scope := c.scope
+	scope.LabelSelector = ""
+	scope.FieldSelector = ""
+	scope.IncludeRelated = false
+	return NewScopedClient(c.Client, scope)
+}
+
+// scopedObject is implemented by pointers to every namespaced API type
+type scopedObject[T any] interface {
+	*T
+	GetNamespace() string
+	GetName() string
+}
+
+// objectRef identifies an object of a kind, used to find related objects
+type objectRef struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// refOf returns a function identifying objects of a kind by their own name
+func refOf[T any, PT scopedObject[T]](kind string) func(item PT) objectRef {
+	return func(item PT) objectRef {
+		return objectRef{Kind: kind, Namespace: item.GetNamespace(), Name: item.GetName()}
+	}
+}
+
+// scopedList lists the selected objects of a single namespace, or of every
+// namespace in scope when namespace is empty
+func scopedList[T any, PT scopedObject[T]](c *ScopedClient, namespace string, opts metav1.ListOptions, list func(namespace string, opts metav1.ListOptions) ([]T, error)) ([]T, error) {
+	if !c.scope.Includes(namespace) {
+		return []T{}, nil
+	}
+
+	items, err := listNamespaces(c.scope, namespace, c.scope.listOptions(opts), list)
+	if err != nil {
+		// Most field selectors only exist for some kinds, no object of a
+		// kind without the field can match it
+		if c.scope.FieldSelector != "" && apierrors.IsBadRequest(err) {
+			kind := fmt.Sprintf("%T", *new(T))
+			if _, warned := c.warned.LoadOrStore(kind, true); !warned {
+				log.WithError(err).Warnw("Field selector not supported, skipping", "type", kind)
+			}
+			return []T{}, nil
+		}
+		return nil, err
+	}
+
+	return inScope[T, PT](c.scope, namespace, items), nil
+}
+
+// listNamespaces lists a single namespace, or every namespace in scope when
+// namespace is empty. Namespaces given by name are listed one by one, users
+// that may only read those would be refused a list across all namespaces
+func listNamespaces[T any](s Scope, namespace string, opts metav1.ListOptions, list func(namespace string, opts metav1.ListOptions) ([]T, error)) ([]T, error) {
+	names, named := s.namedNamespaces()
+	if namespace != "" || !named {
+		return list(namespace, opts)
+	}
+
+	items := []T{}
+	for _, name := range names {
+		listed, err := list(name, opts)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, listed...)
+	}
+
+	return items, nil
+}
+
+// inScope drops the objects of namespaces out of scope from a list across
+// all namespaces
+func inScope[T any, PT scopedObject[T]](s Scope, namespace string, items []T) []T {
+	if namespace != "" {
+		return items
+	}
+
+	scoped := make([]T, 0, len(items))
+	for i := range items {
+		if s.Includes(PT(&items[i]).GetNamespace()) {
+			scoped = append(scoped, items[i])
+		}
+	}
+
+	return scoped
+}
+
+// relatedList lists the selected objects like scopedList, adding the objects
+// related to the selected pods when the scope asks for them
+func relatedList[T any, PT scopedObject[T]](ctx context.Context, c *ScopedClient, namespace string, opts metav1.ListOptions, ref func(item PT) objectRef, list func(namespace string, opts metav1.ListOptions) ([]T, error)) ([]T, error) {
+	selected, err := scopedList[T, PT](c, namespace, opts, list)
+	if err != nil || !c.scope.IncludeRelated || !c.scope.hasSelector() || !c.scope.Includes(namespace) {
+		return selected, err
+	}
+
+	related, err := c.relatedObjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !hasNamespace(related, namespace) {
+		return selected, nil
+	}
+
+	all, err := listNamespaces(c.scope, namespace, opts, list)
+	if err != nil {
+		return nil, err
+	}
+
+	captured := make(map[string]bool, len(selected))
+	for i := range selected {
+		item := PT(&selected[i])
+		captured[item.GetNamespace()+"/"+item.GetName()] = true
+	}
+
+	for _, item := range inScope[T, PT](c.scope, namespace, all) {
+		object := PT(&item)
+		if related[ref(object)] && !captured[object.GetNamespace()+"/"+object.GetName()] {
+			selected = append(selected, item)
+		}
+	}
+
+	return selected, nil
+}
+
+func hasNamespace(refs map[objectRef]bool, namespace string) bool {
+	if namespace == "" {
+		return len(refs) > 0
+	}
+
+	for ref := range refs {
+		if ref.Namespace == namespace {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ResolveRelated finds the objects related to the selected pods ahead of
+// the collectors, so the deadline of a single collector does not decide the
+// lookup for all of them
+func (c *ScopedClient) ResolveRelated(ctx context.Context) error {
+	if !c.scope.IncludeRelated || !c.scope.hasSelector() {
+		return nil
+	}
+
+	_, err := c.relatedObjects(ctx)
+	return err
+}
+
+// relatedObjects finds the objects related to the selected pods once, every
+// later call returns the same result. A lookup cut short by its context is
+// tried again by the next caller
+func (c *ScopedClient) relatedObjects(ctx context.Context) (map[objectRef]bool, error) {
+	c.relatedMu.Lock()
+	defer c.relatedMu.Unlock()
+
+	if c.related != nil || c.relatedErr != nil {
+		return c.related, c.relatedErr
+	}
+
+	related, err := c.findRelated(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			c.relatedErr = err
+		}
+		return nil, err
+	}
+	c.related = related
+
+	return related, nil
+}
+
+func (c *ScopedClient) findRelated(ctx context.Context) (map[objectRef]bool, error) {
+	pods, err := c.GetPods(ctx, corev1.NamespaceAll, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get selected pods: %w", err)
+	}
+
+	refs := make(map[objectRef]bool)
+	podsByNamespace := make(map[string][]corev1.Pod)
+	for _, pod := range pods {
+		refs[objectRef{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name}] = true
+		podsByNamespace[pod.Namespace] = append(podsByNamespace[pod.Namespace], pod)
+
+		addOwners(refs, pod.Namespace, pod.OwnerReferences)
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil {
+				refs[objectRef{Kind: "PersistentVolumeClaim", Namespace: pod.Namespace, Name: volume.PersistentVolumeClaim.ClaimName}] = true
+			}
+		}
+	}
+
+	for namespace, pods := range podsByNamespace {
+		// ReplicaSets and Jobs are in turn owned by Deployments and CronJobs
+		replicaSets, err := c.Client.GetReplicaSets(ctx, namespace, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get replicasets in namespace %s: %w", namespace, err)
+		}
+		for _, replicaSet := range replicaSets {
+			if refs[objectRef{Kind: "ReplicaSet", Namespace: namespace, Name: replicaSet.Name}] {
+				addOwners(refs, namespace, replicaSet.OwnerReferences)
+			}
+		}
+
+		jobs, err := c.Client.GetJobs(ctx, namespace, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get jobs in namespace %s: %w", namespace, err)
+		}
+		for _, job := range jobs {
+			if refs[objectRef{Kind: "Job", Namespace: namespace, Name: job.Name}] {
+				addOwners(refs, namespace, job.OwnerReferences)
+			}
+		}
+
+		services, err := c.Client.GetServices(ctx, namespace, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get services in namespace %s: %w", namespace, err)
+		}
+		for _, service := range services {
+			if len(service.Spec.Selector) == 0 {
+				continue
+			}
+
+			selector := labels.SelectorFromSet(service.Spec.Selector)
+			for _, pod := range pods {
+				if selector.Matches(labels.Set(pod.Labels)) {
+					refs[objectRef{Kind: "Service", Namespace: namespace, Name: service.Name}] = true
+					break
+				}
+			}
+		}
+	}
+
+	return refs, nil
+}
+
+func addOwners(refs map[objectRef]bool, namespace string, owners []metav1.OwnerReference) {
+	for _, owner := range owners {
+		refs[objectRef{Kind: owner.Kind, Namespace: namespace, Name: owner.Name}] = true
+	}
+}
+
+func (c *ScopedClient) GetNamespaces(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+	namespaces, err := c.Client.GetNamespaces(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	scoped := make([]corev1.Namespace, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		if c.scope.Includes(namespace.Name) {
+			scoped = append(scoped, namespace)
+		}
+	}
+
+	return scoped, nil
+}
+
+func (c *ScopedClient) GetPods(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
+	return scopedList(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
+		return c.Client.GetPods(ctx, namespace, opts)
+	})
+}
+
+func (c *ScopedClient) GetPodLogs(ctx context.Context, namespace string, podName string, opts corev1.PodLogOptions) ([]byte, error) {
+	if !c.scope.Includes(namespace) {
+		return []byte{}, nil
+	}
+	return c.Client.GetPodLogs(ctx, namespace, podName, opts)
+}
+
+func (c *ScopedClient) GetConfigMaps(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.ConfigMap, error) {
+	return scopedList(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]corev1.ConfigMap, error) {
+		return c.Client.GetConfigMaps(ctx, namespace, opts)
+	})
+}
+
+func (c *ScopedClient) GetSecrets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Secret, error) {
+	return scopedList(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]corev1.Secret, error) {
+		return c.Client.GetSecrets(ctx, namespace, opts)
+	})
+}
+
+func (c *ScopedClient) ListResources(ctx context.Context, gvr schema.GroupVersionResource, namespaced bool, namespace string, opts metav1.ListOptions) ([]unstructured.Unstructured, error) {
+	// The selectors are meant for the objects in namespaces, cluster scoped
+	// objects such as CRDs or webhooks rarely carry their labels
+	if !namespaced {
+		return c.Client.ListResources(ctx, gvr, namespaced, namespace, opts)
+	}
+
+	return scopedList(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]unstructured.Unstructured, error) {
+		return c.Client.ListResources(ctx, gvr, namespaced, namespace, opts)
+	})
+}
+
+func (c *ScopedClient) GetDeployments(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.Deployment, error) {
+	return relatedList(ctx, c, namespace, opts, refOf[appsv1.Deployment]("Deployment"), func(namespace string, opts metav1.ListOptions) ([]appsv1.Deployment, error) {
+		return c.Client.GetDeployments(ctx, namespace, opts)
+	})
+}
+
+func (c *ScopedClient) GetStatefulSets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.StatefulSet, error) {
+	return relatedList(ctx, c, namespace, opts, refOf[appsv1.StatefulSet]("StatefulSet"), func(namespace string, opts metav1.ListOptions) ([]appsv1.StatefulSet, error) {
+		return c.Client.GetStatefulSets(ctx, namespace, opts)
+	})
+}
+
+func (c *ScopedClient) GetDaemonSets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.DaemonSet, error) {
+	return relatedList(ctx, c, namespace, opts, refOf[appsv1.DaemonSet]("DaemonSet"), func(namespace string, opts metav1.ListOptions) ([]appsv1.DaemonSet, error) {
+		return c.Client.GetDaemonSets(ctx, namespace, opts)
+	})
+}
+
+func (c *ScopedClient) GetReplicaSets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.ReplicaSet, error) {
+	return relatedList(ctx, c, namespace, opts, refOf[appsv1.ReplicaSet]("ReplicaSet"), func(namespace string, opts metav1.ListOptions) ([]appsv1.ReplicaSet, error) {
+		return c.Client.GetReplicaSets(ctx, namespace, opts)
+	})
+}
+
+func (c *ScopedClient) GetJobs(ctx context.Context, namespace string, opts metav1.ListOptions) ([]batchv1.Job, error) {
+	return relatedList(ctx, c, namespace, opts, refOf[batchv1.Job]("Job"), func(namespace string, opts metav1.ListOptions) ([]batchv1.Job, error) {
+		return c.Client.GetJobs(ctx, namespace, opts)
+	})
+}
+
+func (c *ScopedClient) GetCronJobs(ctx context.Context, namespace string, opts metav1.ListOptions) ([]batchv1.CronJob, error) {
+	return relatedList(ctx, c, namespace, opts, refOf[batchv1.CronJob]("CronJob"), func(namespace string, opts metav1.ListOptions) ([]batchv1.CronJob, error) {
+		return c.Client.GetCronJobs(ctx, namespace, opts)
+	})
+}
+
+func (c *ScopedClient) GetServices(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Service, error) {
+	return relatedList(ctx, c, namespace, opts, refOf[corev1.Service]("Service"), func(namespace string, opts metav1.ListOptions) ([]corev1.Service, error) {
+		return c.Client.GetServices(ctx, namespace, opts)
+	})
+}
+
+func (c *ScopedClient) GetEndpoints(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Endpoints, error) {
+	return scopedList(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]corev1.Endpoints, error) {
+		return c.Client.GetEndpoints(ctx, namespace, opts)
+	})
+}
+
+func (c *ScopedClient) GetEndpointSlices(ctx context.Context, namespace string, opts metav1.ListOptions) ([]discoveryv1.EndpointSlice, error) {
+	return scopedList(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]discoveryv1.EndpointSlice, error) {
+		return c.Client.GetEndpointSlices(ctx, namespace, opts)
+	})
+}
+
+func (c *ScopedClient) GetIngresses(ctx context.Context, namespace string, opts metav1.ListOptions) ([]networkingv1.Ingress, error) {
+	return scopedList(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]networkingv1.Ingress, error) {
+		return c.Client.GetIngresses(ctx, namespace, opts)
+	})
+}
+
+func (c *ScopedClient) GetNetworkPolicies(ctx context.Context, namespace string, opts metav1.ListOptions) ([]networkingv1.NetworkPolicy, error) {
+	return scopedList(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]networkingv1.NetworkPolicy, error) {
+		return c.Client.GetNetworkPolicies(ctx, namespace, opts)
+	})
+}
+
+func (c *ScopedClient) GetPersistentVolumeClaims(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.PersistentVolumeClaim, error) {
+	return relatedList(ctx, c, namespace, opts, refOf[corev1.PersistentVolumeClaim]("PersistentVolumeClaim"), func(namespace string, opts metav1.ListOptions) ([]corev1.PersistentVolumeClaim, error) {
+		return c.Client.GetPersistentVolumeClaims(ctx, namespace, opts)
+	})
+}
+
+// Events carry no labels of their own, they are related through the object
+// they are about
+
+func (c *ScopedClient) GetEvents(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Event, error) {
+	about := func(event *corev1.Event) objectRef {
+		return objectRef{Kind: event.InvolvedObject.Kind, Namespace: event.InvolvedObject.Namespace, Name: event.InvolvedObject.Name}
+	}
+	return relatedList(ctx, c, namespace, opts, about, func(namespace string, opts metav1.ListOptions) ([]corev1.Event, error) {
+		return c.Client.GetEvents(ctx, namespace, opts)
+	})
+}
+
+func (c *ScopedClient) GetEventsV1(ctx context.Context, namespace string, opts metav1.ListOptions) ([]eventsv1.Event, error) {
+	about := func(event *eventsv1.Event) objectRef {
+		return objectRef{Kind: event.Regarding.Kind, Namespace: event.Regarding.Namespace, Name: event.Regarding.Name}
+	}
+	return relatedList(ctx, c, namespace, opts, about, func(namespace string, opts metav1.ListOptions) ([]eventsv1.Event, error) {
+		return c.Client.GetEventsV1(ctx, namespace, opts)
+	})
+}
+
+func (c *ScopedClient) GetPodMetrics(ctx context.Context, namespace string, opts metav1.ListOptions) ([]metricsv1beta1.PodMetrics, error) {
+	return scopedList(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]metricsv1beta1.PodMetrics, error) {
+		return c.Client.GetPodMetrics(ctx, namespace, opts)
+	})
+}
+
+func (c *ScopedClient) GetServiceAccounts(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.ServiceAccount, error) {
+	return scopedList(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]corev1.ServiceAccount, error) {
+		return c.Client.GetServiceAccounts(ctx, namespace, opts)
+	})
+}
+
+func (c *ScopedClient) GetRoles(ctx context.Context, namespace string, opts metav1.ListOptions) ([]rbacv1.Role, error) {
+	return scopedList(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]rbacv1.Role, error) {
+		return c.Client.GetRoles(ctx, namespace, opts)
+	})
+}
+
+func (c *ScopedClient) GetRoleBindings(ctx context.Context, namespace string, opts metav1.ListOptions) ([]rbacv1.RoleBinding, error) {
+	return scopedList(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]rbacv1.RoleBinding, error) {
+		return c.Client.GetRoleBindings(ctx, namespace, opts)
+	})
+}
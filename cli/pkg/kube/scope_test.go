@@ -0,0 +1,390 @@
+package kube
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func newScopeMockClient() *MockClient {
+	return &MockClient{
+		GetNamespacesFunc: func(ctx context.Context, opts metav1.ListOptions) ([]corev1.Namespace, error) {
+			return []corev1.Namespace{
+				{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+			}, nil
+		},
+		GetPodsFunc: func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
+			pods := []corev1.Pod{
+				{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team-a"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "team-b"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "dns", Namespace: "kube-system"}},
+			}
+			if namespace == "" {
+				return pods, nil
+			}
+
+			var matching []corev1.Pod
+			for _, pod := range pods {
+				if pod.Namespace == namespace {
+					matching = append(matching, pod)
+				}
+			}
+			return matching, nil
+		},
+	}
+}
+
+func TestScope_Includes(t *testing.T) {
+	tests := []struct {
+		name      string
+		scope     Scope
+		namespace string
+		want      bool
+	}{
+		{name: "empty scope", scope: Scope{}, namespace: "prod", want: true},
+		{name: "exact match", scope: Scope{Namespaces: []string{"prod"}}, namespace: "prod", want: true},
+		{name: "not included", scope: Scope{Namespaces: []string{"prod"}}, namespace: "dev", want: false},
+		{name: "glob match", scope: Scope{Namespaces: []string{"team-*"}}, namespace: "team-a", want: true},
+		{name: "excluded", scope: Scope{ExcludeNamespaces: []string{"kube-*"}}, namespace: "kube-system", want: false},
+		{name: "exclude wins", scope: Scope{Namespaces: []string{"team-*"}, ExcludeNamespaces: []string{"team-b"}}, namespace: "team-b", want: false},
+		{name: "cluster scoped", scope: Scope{Namespaces: []string{"prod"}}, namespace: "", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.scope.Includes(tt.namespace))
+		})
+	}
+}
+
+func TestScope_Validate(t *testing.T) {
+	assert.NoError(t, Scope{Namespaces: []string{"team-*"}, ExcludeNamespaces: []string{"team-[ab]"}}.Validate())
+	assert.Error(t, Scope{ExcludeNamespaces: []string{"team-["}}.Validate())
+}
+
+func TestScope_IsPartial(t *testing.T) {
+	assert.False(t, Scope{}.IsPartial())
+	assert.True(t, Scope{Namespaces: []string{"prod"}}.IsPartial())
+	assert.True(t, Scope{ExcludeNamespaces: []string{"dev"}}.IsPartial())
+}
+
+func TestScopedClient_GetNamespaces(t *testing.T) {
+	client := NewScopedClient(newScopeMockClient(), Scope{Namespaces: []string{"team-*"}})
+
+	namespaces, err := client.GetNamespaces(context.Background(), metav1.ListOptions{})
+	assert.NoError(t, err)
+
+	assert.Len(t, namespaces, 2)
+	assert.Equal(t, "team-a", namespaces[0].Name)
+	assert.Equal(t, "team-b", namespaces[1].Name)
+}
+
+func TestScopedClient_GetPods_AllNamespaces(t *testing.T) {
+	client := NewScopedClient(newScopeMockClient(), Scope{ExcludeNamespaces: []string{"kube-*"}})
+
+	pods, err := client.GetPods(context.Background(), corev1.NamespaceAll, metav1.ListOptions{})
+	assert.NoError(t, err)
+
+	assert.Len(t, pods, 2)
+	assert.Equal(t, "web", pods[0].Name)
+	assert.Equal(t, "api", pods[1].Name)
+}
+
+func TestScopedClient_GetPods_NamedNamespaces(t *testing.T) {
+	mockClient := newScopeMockClient()
+	listPods := mockClient.GetPodsFunc
+	var listed []string
+	mockClient.GetPodsFunc = func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
+		listed = append(listed, namespace)
+		return listPods(ctx, namespace, opts)
+	}
+	client := NewScopedClient(mockClient, Scope{Namespaces: []string{"team-b", "team-a", "team-b"}})
+
+	pods, err := client.GetPods(context.Background(), corev1.NamespaceAll, metav1.ListOptions{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"team-a", "team-b"}, listed, "named namespaces should be listed one by one")
+	assert.Len(t, pods, 2)
+	assert.Equal(t, "web", pods[0].Name)
+	assert.Equal(t, "api", pods[1].Name)
+
+	// Globs can only be matched against a list across all namespaces
+	listed = nil
+	client = NewScopedClient(mockClient, Scope{Namespaces: []string{"team-*"}})
+	pods, err = client.GetPods(context.Background(), corev1.NamespaceAll, metav1.ListOptions{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{""}, listed)
+	assert.Len(t, pods, 2)
+}
+
+func TestScopedClient_GetPods_ExcludedNamespace(t *testing.T) {
+	mockClient := newScopeMockClient()
+	called := false
+	mockClient.GetPodsFunc = func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
+		called = true
+		return nil, nil
+	}
+	client := NewScopedClient(mockClient, Scope{Namespaces: []string{"team-a"}})
+
+	pods, err := client.GetPods(context.Background(), "team-b", metav1.ListOptions{})
+	assert.NoError(t, err)
+
+	assert.Empty(t, pods)
+	assert.False(t, called, "excluded namespaces should not be queried")
+}
+
+func TestScopedClient_GetPodLogs_ExcludedNamespace(t *testing.T) {
+	mockClient := newScopeMockClient()
+	mockClient.GetPodLogsFunc = func(ctx context.Context, namespace string, podName string, opts corev1.PodLogOptions) ([]byte, error) {
+		return []byte("log line\n"), nil
+	}
+	client := NewScopedClient(mockClient, Scope{ExcludeNamespaces: []string{"team-b"}})
+
+	logs, err := client.GetPodLogs(context.Background(), "team-b", "api", corev1.PodLogOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, logs)
+
+	logs, err = client.GetPodLogs(context.Background(), "team-a", "web", corev1.PodLogOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "log line\n", string(logs))
+}
+
+func TestScope_Validate_Selectors(t *testing.T) {
+	assert.NoError(t, Scope{LabelSelector: "app=checkout,tier!=db", FieldSelector: "status.phase!=Running"}.Validate())
+	assert.Error(t, Scope{LabelSelector: "app in ("}.Validate())
+	assert.Error(t, Scope{FieldSelector: "status.phase"}.Validate())
+	assert.True(t, Scope{LabelSelector: "app=checkout"}.IsPartial())
+}
+
+func TestScopedClient_GetPods_Selectors(t *testing.T) {
+	mockClient := newScopeMockClient()
+	var got metav1.ListOptions
+	mockClient.GetPodsFunc = func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
+		got = opts
+		return []corev1.Pod{}, nil
+	}
+	client := NewScopedClient(mockClient, Scope{LabelSelector: "app=checkout", FieldSelector: "status.phase!=Running"})
+
+	_, err := client.GetPods(context.Background(), "team-a", metav1.ListOptions{LabelSelector: "tier=web"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "tier=web,app=checkout", got.LabelSelector)
+	assert.Equal(t, "status.phase!=Running", got.FieldSelector)
+}
+
+func TestScopedClient_UnsupportedFieldSelector(t *testing.T) {
+	mockClient := newScopeMockClient()
+	mockClient.GetConfigMapsFunc = func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.ConfigMap, error) {
+		return nil, apierrors.NewBadRequest(`field label not supported: status.phase`)
+	}
+	client := NewScopedClient(mockClient, Scope{FieldSelector: "status.phase!=Running"})
+
+	configMaps, err := client.GetConfigMaps(context.Background(), "team-a", metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, configMaps)
+
+	// Without a field selector the error is not swallowed
+	client = NewScopedClient(mockClient, Scope{LabelSelector: "app=checkout"})
+	_, err = client.GetConfigMaps(context.Background(), "team-a", metav1.ListOptions{})
+	assert.Error(t, err)
+}
+
+func TestScopedClient_ListResources_ClusterScoped(t *testing.T) {
+	mockClient := newScopeMockClient()
+	var got metav1.ListOptions
+	mockClient.ListResourcesFunc = func(ctx context.Context, gvr schema.GroupVersionResource, namespaced bool, namespace string, opts metav1.ListOptions) ([]unstructured.Unstructured, error) {
+		got = opts
+		return []unstructured.Unstructured{{Object: map[string]interface{}{"metadata": map[string]interface{}{"name": "high"}}}}, nil
+	}
+	client := NewScopedClient(mockClient, Scope{LabelSelector: "app=checkout", FieldSelector: "status.phase!=Running"})
+	priorityClasses := schema.GroupVersionResource{Group: "scheduling.k8s.io", Version: "v1", Resource: "priorityclasses"}
+
+	items, err := client.ListResources(context.Background(), priorityClasses, false, "", metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Empty(t, got.LabelSelector)
+	assert.Empty(t, got.FieldSelector)
+
+	_, err = client.ListResources(context.Background(), priorityClasses, true, "", metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "app=checkout", got.LabelSelector)
+}
+
+func TestScopedClient_WithoutSelectors(t *testing.T) {
+	mockClient := newScopeMockClient()
+	var got metav1.ListOptions
+	mockClient.GetSecretsFunc = func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Secret, error) {
+		got = opts
+		return []corev1.Secret{}, nil
+	}
+	client := NewScopedClient(mockClient, Scope{Namespaces: []string{"team-a"}, LabelSelector: "app=checkout", FieldSelector: "type=Opaque"}).WithoutSelectors()
+
+	_, err := client.GetSecrets(context.Background(), "team-a", metav1.ListOptions{LabelSelector: "owner=helm"})
+	assert.NoError(t, err)
+	assert.Equal(t, "owner=helm", got.LabelSelector)
+	assert.Empty(t, got.FieldSelector)
+
+	// The namespaces of the scope still apply
+	got = metav1.ListOptions{}
+	secrets, err := client.GetSecrets(context.Background(), "team-b", metav1.ListOptions{LabelSelector: "owner=helm"})
+	assert.NoError(t, err)
+	assert.Empty(t, secrets)
+	assert.Empty(t, got.LabelSelector)
+}
+
+func newRelatedMockClient() *MockClient {
+	isController := true
+	mockClient := newScopeMockClient()
+	mockClient.GetPodsFunc = func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
+		if opts.LabelSelector != "app=checkout" {
+			return nil, errors.New("unexpected selector " + opts.LabelSelector)
+		}
+		return []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "checkout-7d9f-abcde",
+					Namespace:       "shop",
+					Labels:          map[string]string{"app": "checkout"},
+					OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "checkout-7d9f", Controller: &isController}},
+				},
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{
+						{Name: "data", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "checkout-data"}}},
+					},
+				},
+			},
+		}, nil
+	}
+	mockClient.GetReplicaSetsFunc = func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.ReplicaSet, error) {
+		if opts.LabelSelector != "" {
+			// The ReplicaSet is not labelled app=checkout
+			return []appsv1.ReplicaSet{}, nil
+		}
+		return []appsv1.ReplicaSet{
+			{ObjectMeta: metav1.ObjectMeta{
+				Name:            "checkout-7d9f",
+				Namespace:       "shop",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "checkout", Controller: &isController}},
+			}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "cart-5c8b", Namespace: "shop"}},
+		}, nil
+	}
+	mockClient.GetDeploymentsFunc = func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.Deployment, error) {
+		if opts.LabelSelector != "" {
+			return []appsv1.Deployment{}, nil
+		}
+		return []appsv1.Deployment{
+			{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "cart", Namespace: "shop"}},
+		}, nil
+	}
+	mockClient.GetJobsFunc = func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]batchv1.Job, error) {
+		return []batchv1.Job{}, nil
+	}
+	mockClient.GetServicesFunc = func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Service, error) {
+		if opts.LabelSelector != "" {
+			return []corev1.Service{}, nil
+		}
+		return []corev1.Service{
+			{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop"}, Spec: corev1.ServiceSpec{Selector: map[string]string{"app": "checkout"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "cart", Namespace: "shop"}, Spec: corev1.ServiceSpec{Selector: map[string]string{"app": "cart"}}},
+		}, nil
+	}
+	mockClient.GetPersistentVolumeClaimsFunc = func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.PersistentVolumeClaim, error) {
+		if opts.LabelSelector != "" {
+			return []corev1.PersistentVolumeClaim{}, nil
+		}
+		return []corev1.PersistentVolumeClaim{
+			{ObjectMeta: metav1.ObjectMeta{Name: "checkout-data", Namespace: "shop"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "cart-data", Namespace: "shop"}},
+		}, nil
+	}
+	mockClient.GetEventsFunc = func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Event, error) {
+		if opts.LabelSelector != "" {
+			return []corev1.Event{}, nil
+		}
+		return []corev1.Event{
+			{ObjectMeta: metav1.ObjectMeta{Name: "checkout-7d9f-abcde.1", Namespace: "shop"}, InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "shop", Name: "checkout-7d9f-abcde"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "cart.1", Namespace: "shop"}, InvolvedObject: corev1.ObjectReference{Kind: "Deployment", Namespace: "shop", Name: "cart"}},
+		}, nil
+	}
+	return mockClient
+}
+
+func TestScopedClient_IncludeRelated(t *testing.T) {
+	client := NewScopedClient(newRelatedMockClient(), Scope{LabelSelector: "app=checkout", IncludeRelated: true})
+	ctx := context.Background()
+
+	replicaSets, err := client.GetReplicaSets(ctx, "shop", metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, replicaSets, 1)
+	assert.Equal(t, "checkout-7d9f", replicaSets[0].Name)
+
+	deployments, err := client.GetDeployments(ctx, "shop", metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, deployments, 1)
+	assert.Equal(t, "checkout", deployments[0].Name)
+
+	services, err := client.GetServices(ctx, "shop", metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, services, 1)
+	assert.Equal(t, "checkout", services[0].Name)
+
+	claims, err := client.GetPersistentVolumeClaims(ctx, "shop", metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, claims, 1)
+	assert.Equal(t, "checkout-data", claims[0].Name)
+
+	events, err := client.GetEvents(ctx, "shop", metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "checkout-7d9f-abcde.1", events[0].Name)
+
+	// Namespaces without selected pods are not listed a second time
+	deployments, err = client.GetDeployments(ctx, "other", metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, deployments)
+}
+
+func TestScopedClient_WithoutIncludeRelated(t *testing.T) {
+	client := NewScopedClient(newRelatedMockClient(), Scope{LabelSelector: "app=checkout"})
+
+	deployments, err := client.GetDeployments(context.Background(), "shop", metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, deployments)
+}
+
+func TestScopedClient_IncludeRelated_RetriedAfterCancel(t *testing.T) {
+	mockClient := newRelatedMockClient()
+	getPods := mockClient.GetPodsFunc
+	mockClient.GetPodsFunc = func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return getPods(ctx, namespace, opts)
+	}
+	client := NewScopedClient(mockClient, Scope{LabelSelector: "app=checkout", IncludeRelated: true})
+
+	// A collector that ran out of time does not fail the lookup for others
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.GetDeployments(cancelled, "shop", metav1.ListOptions{})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	assert.NoError(t, client.ResolveRelated(context.Background()))
+	deployments, err := client.GetDeployments(context.Background(), "shop", metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, deployments, 1)
+}
@@ -0,0 +1,69 @@
+package kube
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (k *KubeClient) GetPersistentVolumes(ctx context.Context, opts metav1.ListOptions) ([]corev1.PersistentVolume, error) {
+	return listPages(ctx, k, "persistentvolumes", "", opts, func(ctx context.Context, opts metav1.ListOptions) ([]corev1.PersistentVolume, metav1.ListInterface, error) {
+		list, err := k.clientset.CoreV1().PersistentVolumes().List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetPersistentVolumeClaims(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.PersistentVolumeClaim, error) {
+	return listPages(ctx, k, "persistentvolumeclaims", namespace, opts, func(ctx context.Context, opts metav1.ListOptions) ([]corev1.PersistentVolumeClaim, metav1.ListInterface, error) {
+		list, err := k.clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetStorageClasses(ctx context.Context, opts metav1.ListOptions) ([]storagev1.StorageClass, error) {
+	return listPages(ctx, k, "storageclasses", "", opts, func(ctx context.Context, opts metav1.ListOptions) ([]storagev1.StorageClass, metav1.ListInterface, error) {
+		list, err := k.clientset.StorageV1().StorageClasses().List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetVolumeAttachments(ctx context.Context, opts metav1.ListOptions) ([]storagev1.VolumeAttachment, error) {
+	return listPages(ctx, k, "volumeattachments", "", opts, func(ctx context.Context, opts metav1.ListOptions) ([]storagev1.VolumeAttachment, metav1.ListInterface, error) {
+		list, err := k.clientset.StorageV1().VolumeAttachments().List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetCSIDrivers(ctx context.Context, opts metav1.ListOptions) ([]storagev1.CSIDriver, error) {
+	return listPages(ctx, k, "csidrivers", "", opts, func(ctx context.Context, opts metav1.ListOptions) ([]storagev1.CSIDriver, metav1.ListInterface, error) {
+		list, err := k.clientset.StorageV1().CSIDrivers().List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetCSINodes(ctx context.Context, opts metav1.ListOptions) ([]storagev1.CSINode, error) {
+	return listPages(ctx, k, "csinodes", "", opts, func(ctx context.Context, opts metav1.ListOptions) ([]storagev1.CSINode, metav1.ListInterface, error) {
+		list, err := k.clientset.StorageV1().CSINodes().List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
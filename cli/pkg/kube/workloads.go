@@ -0,0 +1,69 @@
+package kube
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (k *KubeClient) GetDeployments(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.Deployment, error) {
+	return listPages(ctx, k, "deployments", namespace, opts, func(ctx context.Context, opts metav1.ListOptions) ([]appsv1.Deployment, metav1.ListInterface, error) {
+		list, err := k.clientset.AppsV1().Deployments(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetStatefulSets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.StatefulSet, error) {
+	return listPages(ctx, k, "statefulsets", namespace, opts, func(ctx context.Context, opts metav1.ListOptions) ([]appsv1.StatefulSet, metav1.ListInterface, error) {
+		list, err := k.clientset.AppsV1().StatefulSets(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetDaemonSets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.DaemonSet, error) {
+	return listPages(ctx, k, "daemonsets", namespace, opts, func(ctx context.Context, opts metav1.ListOptions) ([]appsv1.DaemonSet, metav1.ListInterface, error) {
+		list, err := k.clientset.AppsV1().DaemonSets(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetReplicaSets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.ReplicaSet, error) {
+	return listPages(ctx, k, "replicasets", namespace, opts, func(ctx context.Context, opts metav1.ListOptions) ([]appsv1.ReplicaSet, metav1.ListInterface, error) {
+		list, err := k.clientset.AppsV1().ReplicaSets(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetJobs(ctx context.Context, namespace string, opts metav1.ListOptions) ([]batchv1.Job, error) {
+	return listPages(ctx, k, "jobs", namespace, opts, func(ctx context.Context, opts metav1.ListOptions) ([]batchv1.Job, metav1.ListInterface, error) {
+		list, err := k.clientset.BatchV1().Jobs(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
+
+func (k *KubeClient) GetCronJobs(ctx context.Context, namespace string, opts metav1.ListOptions) ([]batchv1.CronJob, error) {
+	return listPages(ctx, k, "cronjobs", namespace, opts, func(ctx context.Context, opts metav1.ListOptions) ([]batchv1.CronJob, metav1.ListInterface, error) {
+		list, err := k.clientset.BatchV1().CronJobs(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Items, list, nil
+	})
+}
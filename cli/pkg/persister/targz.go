@@ -30,14 +30,27 @@ func NewTarGzPersister() (*TarGzPersister, error) {
 }
 
 func (p *TarGzPersister) Persist(resource collector.ClusterResource) error {
-	path := filepath.Join(p.basePath, resource.Kind)
-	if err := os.MkdirAll(path, 0755); err != nil {
+	filePath := filepath.Join(p.basePath, resourcePath(resource))
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	fileName := fmt.Sprintf("%s.json", resource.Name)
-	filePath := filepath.Join(path, fileName)
+	// Raw content such as logs is stored as is
+	if raw, ok := resource.Data.([]byte); ok {
+		if err := os.WriteFile(filePath, raw, 0644); err != nil {
+			return fmt.Errorf("failed to create file: %w", err)
+		}
+		return nil
+	}
+
+	return writeJSON(filePath, resource.Data)
+}
 
+func (p *TarGzPersister) PersistFile(name string, data any) error {
+	return writeJSON(filepath.Join(p.basePath, name), data)
+}
+
+func writeJSON(filePath string, data any) error {
 	file, err := os.Create(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to create file: %w", err)
@@ -46,7 +59,7 @@ func (p *TarGzPersister) Persist(resource collector.ClusterResource) error {
 
 	encoder := json.NewEncoder(file)
 	encoder.SetIndent("", "  ")
-	return encoder.Encode(resource.Data)
+	return encoder.Encode(data)
 }
 
 func (p *TarGzPersister) Finalize() error {
@@ -109,6 +122,26 @@ func (p *TarGzPersister) Finalize() error {
 	})
 }
 
+// resourcePath returns the path of a resource inside the snapshot, namespaced
+// resources get their own directory so equally named objects don't collide.
+// Raw content is stored under its name as is, anything else as json
+func resourcePath(resource collector.ClusterResource) string {
+	dir := resource.Kind
+	if resource.Group != "" {
+		dir = fmt.Sprintf("%s.%s", resource.Kind, resource.Group)
+	}
+
+	if namespace := resource.Metadata["namespace"]; namespace != "" {
+		dir = filepath.Join(dir, namespace)
+	}
+
+	if _, ok := resource.Data.([]byte); ok {
+		return filepath.Join(dir, filepath.FromSlash(resource.Name))
+	}
+
+	return filepath.Join(dir, fmt.Sprintf("%s.json", resource.Name))
+}
+
 func (p *TarGzPersister) cleanup() {
 	if err := os.RemoveAll(p.basePath); err != nil {
 		log.WithError(err).Errorf("Failed to cleanup tmp dir %s", p.basePath)
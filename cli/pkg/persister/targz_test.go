@@ -1,8 +1,10 @@
 package persister
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
 	"testing"
 	"time"
@@ -224,3 +226,73 @@ func TestTarGzPersister_TimezoneConsistency(t *testing.T) {
 		}
 	}
 }
+
+func TestTarGzPersister_ResourcePath(t *testing.T) {
+	tests := []struct {
+		resource collector.ClusterResource
+		expected string
+	}{
+		{
+			resource: collector.ClusterResource{Kind: "namespace", Name: "default"},
+			expected: "namespace/default.json",
+		},
+		{
+			resource: collector.ClusterResource{
+				Kind:     "pod",
+				Name:     "web-123",
+				Metadata: map[string]string{"namespace": "default"},
+			},
+			expected: "pod/default/web-123.json",
+		},
+		{
+			resource: collector.ClusterResource{
+				Kind:     "deployment",
+				Group:    "apps",
+				Version:  "v1",
+				Name:     "web",
+				Metadata: map[string]string{"namespace": "production"},
+			},
+			expected: "deployment.apps/production/web.json",
+		},
+		{
+			resource: collector.ClusterResource{
+				Kind:     "log",
+				Name:     "web-123/nginx.previous.log",
+				Data:     []byte("started\n"),
+				Metadata: map[string]string{"namespace": "default"},
+			},
+			expected: "log/default/web-123/nginx.previous.log",
+		},
+	}
+
+	for _, tt := range tests {
+		if path := resourcePath(tt.resource); path != tt.expected {
+			t.Errorf("Expected path %s, got %s", tt.expected, path)
+		}
+	}
+}
+
+func TestTarGzPersister_PersistFile(t *testing.T) {
+	persister, err := NewTarGzPersister()
+	if err != nil {
+		t.Fatalf("Failed to create persister: %v", err)
+	}
+	defer persister.cleanup()
+
+	if err := persister.PersistFile("cluster.json", map[string]string{"context": "prod"}); err != nil {
+		t.Fatalf("Failed to persist file: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(persister.basePath, "cluster.json"))
+	if err != nil {
+		t.Fatalf("Failed to read persisted file: %v", err)
+	}
+
+	var data map[string]string
+	if err := json.Unmarshal(content, &data); err != nil {
+		t.Fatalf("Persisted file is not valid json: %v", err)
+	}
+	if data["context"] != "prod" {
+		t.Errorf("Expected context prod, got %s", data["context"])
+	}
+}
@@ -7,5 +7,7 @@ import (
 
 type Persister interface {
 	Persist(resource collector.ClusterResource) error
+	// PersistFile stores data as json under name at the root of the snapshot
+	PersistFile(name string, data any) error
 	Finalize() error
 }
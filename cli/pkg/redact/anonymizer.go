@@ -0,0 +1,337 @@
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Identifier prefixes of anonymized names, an anonymized pod is for example
+// pod-3f9a2c1b7e
+const (
+	PrefixCluster   = "cluster"
+	PrefixNamespace = "ns"
+	PrefixPod       = "pod"
+	PrefixNode      = "node"
+	PrefixHost      = "host"
+)
+
+// publicIdentifiers are well known names that identify nobody, keeping them
+// makes snapshots easier to read
+var publicIdentifiers = map[string]bool{
+	"default":         true,
+	"kube-system":     true,
+	"kube-public":     true,
+	"kube-node-lease": true,
+	"localhost":       true,
+}
+
+// typeFields hold API groups and kinds rather than names, a namespace called
+// apps must not turn apps/v1 into something else
+var typeFields = map[string]bool{
+	"apiVersion": true,
+	"apiGroup":   true,
+	"apiGroups":  true,
+	"kind":       true,
+}
+
+// labelFields are the objects whose keys are chosen by users and may hold
+// identifiers, the keys of every other object are part of the schema
+var labelFields = map[string]bool{
+	"labels":       true,
+	"annotations":  true,
+	"matchLabels":  true,
+	"nodeSelector": true,
+	"selector":     true,
+}
+
+var (
+	// tokenPattern matches runs of characters that can make up a name, dots
+	// separate the labels of DNS names
+	tokenPattern = regexp.MustCompile(`[A-Za-z0-9_.-]+`)
+	ipv6Pattern  = regexp.MustCompile(`(?i)[0-9a-f]{0,4}(?::[0-9a-f]{0,4}){2,7}`)
+)
+
+// Anonymizer replaces namespace, pod, node and host names and internal IP
+// addresses with keyed hashes. The same key gives the same replacement for
+// the same identifier everywhere, so references between resources and
+// mentions in logs still match up
+type Anonymizer struct {
+	key []byte
+	// replacements maps original identifiers to their anonymized form
+	replacements map[string]string
+	// originals maps anonymized identifiers back to the original
+	originals map[string]string
+}
+
+func NewAnonymizer(key []byte) *Anonymizer {
+	return &Anonymizer{
+		key:          key,
+		replacements: make(map[string]string),
+		originals:    make(map[string]string),
+	}
+}
+
+// AddIdentifier registers a name to be anonymized from now on
+func (a *Anonymizer) AddIdentifier(prefix string, value string) {
+	if len(value) < 2 || publicIdentifiers[value] {
+		return
+	}
+	if _, exists := a.replacements[value]; exists {
+		return
+	}
+
+	a.register(value, func(attempt int) string {
+		return fmt.Sprintf("%s-%s", prefix, a.hash(value, attempt)[:10])
+	})
+}
+
+// Learn registers the identifiers found in a resource: its namespace and
+// the names of namespaces, pods, nodes and hosts
+func (a *Anonymizer) Learn(kind string, data interface{}) {
+	object, ok := data.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	meta, _ := object["metadata"].(map[string]interface{})
+	name, _ := meta["name"].(string)
+	if namespace, ok := meta["namespace"].(string); ok {
+		a.AddIdentifier(PrefixNamespace, namespace)
+	}
+
+	switch kind {
+	case "namespace":
+		a.AddIdentifier(PrefixNamespace, name)
+	case "pod":
+		a.AddIdentifier(PrefixPod, name)
+		spec, _ := object["spec"].(map[string]interface{})
+		if nodeName, ok := spec["nodeName"].(string); ok {
+			a.AddIdentifier(PrefixNode, nodeName)
+		}
+		if hostname, ok := spec["hostname"].(string); ok {
+			a.AddIdentifier(PrefixHost, hostname)
+		}
+	case "event":
+		regarding, _ := object["regarding"].(map[string]interface{})
+		kind, _ := regarding["kind"].(string)
+		namespace, _ := regarding["namespace"].(string)
+		name, _ := regarding["name"].(string)
+		a.learnObject(kind, namespace, name)
+	case "node":
+		a.AddIdentifier(PrefixNode, name)
+		status, _ := object["status"].(map[string]interface{})
+		addresses, _ := status["addresses"].([]interface{})
+		for _, item := range addresses {
+			address, _ := item.(map[string]interface{})
+			switch address["type"] {
+			case "Hostname", "InternalDNS", "ExternalDNS":
+				if host, ok := address["address"].(string); ok {
+					a.AddIdentifier(PrefixHost, host)
+				}
+			}
+		}
+	}
+}
+
+// LearnMetadata registers the identifiers in the metadata of a resource,
+// which names the pod of a log and the object an event is about
+func (a *Anonymizer) LearnMetadata(metadata map[string]string) {
+	a.AddIdentifier(PrefixNamespace, metadata["namespace"])
+	a.AddIdentifier(PrefixPod, metadata["pod"])
+	a.AddIdentifier(PrefixNode, metadata["node"])
+	a.learnObject(metadata["involvedObjectKind"], metadata["involvedObjectNamespace"], metadata["involvedObjectName"])
+}
+
+// learnObject registers the identifiers of an object reference
+func (a *Anonymizer) learnObject(kind string, namespace string, name string) {
+	a.AddIdentifier(PrefixNamespace, namespace)
+
+	switch kind {
+	case "Namespace":
+		a.AddIdentifier(PrefixNamespace, name)
+	case "Pod":
+		a.AddIdentifier(PrefixPod, name)
+	case "Node":
+		a.AddIdentifier(PrefixNode, name)
+	}
+}
+
+// Data returns an anonymized copy of a value. Strings are anonymized, as
+// are the keys of labels and annotations, while API types and the keys of
+// the schema are kept
+func (a *Anonymizer) Data(data interface{}) (interface{}, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	var anonymized interface{}
+	if err := json.Unmarshal(raw, &anonymized); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal data: %w", err)
+	}
+
+	return a.value(anonymized, ""), nil
+}
+
+// value anonymizes a value found under the given key of its parent object
+func (a *Anonymizer) value(value interface{}, field string) interface{} {
+	if typeFields[field] {
+		return value
+	}
+
+	switch typed := value.(type) {
+	case string:
+		return a.Text(typed)
+	case map[string]interface{}:
+		anonymized := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			if labelFields[field] {
+				anonymized[a.Text(key)] = a.value(item, "")
+				continue
+			}
+			anonymized[key] = a.value(item, key)
+		}
+		return anonymized
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = a.value(item, field)
+		}
+		return typed
+	default:
+		return value
+	}
+}
+
+// Text replaces every known identifier and internal IP address in a text
+func (a *Anonymizer) Text(text string) string {
+	text = tokenPattern.ReplaceAllStringFunc(text, a.token)
+
+	return ipv6Pattern.ReplaceAllStringFunc(text, func(match string) string {
+		ip := net.ParseIP(match)
+		if ip == nil || ip.To4() != nil || !ip.IsPrivate() {
+			return match
+		}
+		return a.ip(match, ip)
+	})
+}
+
+// token anonymizes a run of name characters, either as a whole or by the
+// longest known sequence of its dot separated labels, so a namespace inside
+// web.payments.svc.cluster.local is still found
+func (a *Anonymizer) token(token string) string {
+	if replacement, exists := a.replacements[token]; exists {
+		return replacement
+	}
+
+	if ip := net.ParseIP(token); ip != nil && ip.To4() != nil {
+		if ip.IsPrivate() {
+			return a.ip(token, ip)
+		}
+		return token
+	}
+
+	labels := strings.Split(token, ".")
+	if len(labels) == 1 {
+		return token
+	}
+
+	var result []string
+	for i := 0; i < len(labels); {
+		matched := false
+		for j := len(labels); j > i; j-- {
+			if replacement, exists := a.replacements[strings.Join(labels[i:j], ".")]; exists {
+				result = append(result, replacement)
+				i = j
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			result = append(result, labels[i])
+			i++
+		}
+	}
+
+	return strings.Join(result, ".")
+}
+
+// ip anonymizes an internal address into another address of the same
+// family, so fields holding IPs stay valid
+func (a *Anonymizer) ip(original string, ip net.IP) string {
+	if replacement, exists := a.replacements[original]; exists {
+		return replacement
+	}
+
+	return a.register(original, func(attempt int) string {
+		sum, _ := hex.DecodeString(a.hash(original, attempt))
+		if ip.To4() != nil {
+			return net.IPv4(10, sum[0], sum[1], sum[2]).String()
+		}
+		return net.IP(append([]byte{0xfd, 0x00}, sum[:14]...)).String()
+	})
+}
+
+// register stores the replacement of an identifier, generating another one
+// in the unlikely case it collides with the replacement of a different
+// identifier
+func (a *Anonymizer) register(original string, generate func(attempt int) string) string {
+	for attempt := 0; ; attempt++ {
+		replacement := generate(attempt)
+		if existing, taken := a.originals[replacement]; taken && existing != original {
+			continue
+		}
+
+		a.replacements[original] = replacement
+		a.originals[replacement] = original
+		return replacement
+	}
+}
+
+func (a *Anonymizer) hash(value string, attempt int) string {
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(value))
+	if attempt > 0 {
+		fmt.Fprintf(mac, "#%d", attempt)
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Mapping returns every anonymized identifier with its original value
+func (a *Anonymizer) Mapping() map[string]string {
+	mapping := make(map[string]string, len(a.originals))
+	for anonymized, original := range a.originals {
+		mapping[anonymized] = original
+	}
+	return mapping
+}
+
+// Deanonymize replaces every anonymized identifier in a text with its
+// original value
+func Deanonymize(text string, mapping map[string]string) string {
+	anonymized := make([]string, 0, len(mapping))
+	for identifier := range mapping {
+		anonymized = append(anonymized, identifier)
+	}
+
+	// Longer identifiers first, so 10.1.2.30 is not read as 10.1.2.3
+	sort.Slice(anonymized, func(i, j int) bool {
+		if len(anonymized[i]) != len(anonymized[j]) {
+			return len(anonymized[i]) > len(anonymized[j])
+		}
+		return anonymized[i] < anonymized[j]
+	})
+
+	pairs := make([]string, 0, len(mapping)*2)
+	for _, identifier := range anonymized {
+		pairs = append(pairs, identifier, mapping[identifier])
+	}
+
+	return strings.NewReplacer(pairs...).Replace(text)
+}
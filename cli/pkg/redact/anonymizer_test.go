@@ -0,0 +1,135 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestAnonymizer() *Anonymizer {
+	anonymizer := NewAnonymizer([]byte("test-key"))
+	anonymizer.Learn("namespace", map[string]interface{}{"metadata": map[string]interface{}{"name": "payments"}})
+	anonymizer.Learn("pod", map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "api-7d9f8-x2x9q", "namespace": "payments"},
+		"spec":     map[string]interface{}{"nodeName": "ip-10-0-1-5.ec2.internal"},
+	})
+	return anonymizer
+}
+
+func TestAnonymizer_Stable(t *testing.T) {
+	first := newTestAnonymizer()
+	second := newTestAnonymizer()
+
+	assert.Equal(t, first.Text("payments"), second.Text("payments"))
+	assert.True(t, strings.HasPrefix(first.Text("payments"), "ns-"))
+	assert.True(t, strings.HasPrefix(first.Text("api-7d9f8-x2x9q"), "pod-"))
+	assert.True(t, strings.HasPrefix(first.Text("ip-10-0-1-5.ec2.internal"), "node-"))
+
+	other := NewAnonymizer([]byte("other-key"))
+	other.AddIdentifier(PrefixNamespace, "payments")
+	assert.NotEqual(t, first.Text("payments"), other.Text("payments"))
+}
+
+func TestAnonymizer_Text(t *testing.T) {
+	anonymizer := newTestAnonymizer()
+	namespace := anonymizer.Text("payments")
+	pod := anonymizer.Text("api-7d9f8-x2x9q")
+
+	text := anonymizer.Text("GET http://api.payments.svc.cluster.local from 10.0.3.7 and 8.8.8.8 in default by payments/api-7d9f8-x2x9q payments-db")
+
+	assert.NotContains(t, text, "payments.svc")
+	assert.Contains(t, text, "api."+namespace+".svc.cluster.local")
+	assert.Contains(t, text, namespace+"/"+pod)
+	assert.NotContains(t, text, "10.0.3.7")
+	assert.Contains(t, text, "8.8.8.8")
+	assert.Contains(t, text, "in default by")
+	// Other names merely containing an identifier are left alone
+	assert.Contains(t, text, "payments-db")
+
+	// The same address is always replaced the same way
+	assert.Equal(t, anonymizer.Text("10.0.3.7"), anonymizer.Text("10.0.3.7"))
+	assert.Regexp(t, `^10\.\d+\.\d+\.\d+$`, anonymizer.Text("10.0.3.7"))
+	assert.Regexp(t, `^fd00:`, anonymizer.Text("fd12:3456:789a::1"))
+}
+
+func TestAnonymizer_Data(t *testing.T) {
+	anonymizer := newTestAnonymizer()
+	namespace := anonymizer.Text("payments")
+	pod := anonymizer.Text("api-7d9f8-x2x9q")
+
+	data, err := anonymizer.Data(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":      "api-7d9f8",
+			"namespace": "payments",
+			"labels":    map[string]interface{}{"app": "api"},
+			"annotations": map[string]interface{}{
+				"payments/owner": "api-7d9f8-x2x9q",
+			},
+		},
+		"pods": []interface{}{"payments/api-7d9f8-x2x9q"},
+	})
+	assert.NoError(t, err)
+
+	object := data.(map[string]interface{})
+	meta := object["metadata"].(map[string]interface{})
+	assert.Equal(t, namespace, meta["namespace"])
+	assert.Equal(t, "api-7d9f8", meta["name"])
+	assert.Equal(t, map[string]interface{}{"app": "api"}, meta["labels"])
+	assert.Equal(t, map[string]interface{}{namespace + "/owner": pod}, meta["annotations"])
+	assert.Equal(t, []interface{}{namespace + "/" + pod}, object["pods"])
+}
+
+func TestAnonymizer_DataKeepsSchema(t *testing.T) {
+	anonymizer := NewAnonymizer([]byte("test-key"))
+	anonymizer.AddIdentifier(PrefixNamespace, "apps")
+	anonymizer.AddIdentifier(PrefixNamespace, "status")
+	namespace := anonymizer.Text("apps")
+
+	data, err := anonymizer.Data(map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":      "web",
+			"namespace": "apps",
+			"ownerReferences": []interface{}{
+				map[string]interface{}{"apiVersion": "apps/v1", "kind": "ReplicaSet", "name": "web-1"},
+			},
+		},
+		"status": map[string]interface{}{"replicas": 1},
+		"rules":  []interface{}{map[string]interface{}{"apiGroups": []interface{}{"apps"}}},
+	})
+	assert.NoError(t, err)
+
+	object := data.(map[string]interface{})
+	meta := object["metadata"].(map[string]interface{})
+	assert.Equal(t, "apps/v1", object["apiVersion"])
+	assert.Equal(t, namespace, meta["namespace"])
+	assert.Equal(t, "apps/v1", meta["ownerReferences"].([]interface{})[0].(map[string]interface{})["apiVersion"])
+	assert.Equal(t, map[string]interface{}{"replicas": float64(1)}, object["status"])
+	assert.Equal(t, []interface{}{"apps"}, object["rules"].([]interface{})[0].(map[string]interface{})["apiGroups"])
+}
+
+func TestAnonymizer_LearnReferences(t *testing.T) {
+	anonymizer := NewAnonymizer([]byte("test-key"))
+	anonymizer.LearnMetadata(map[string]string{"namespace": "payments", "pod": "api-7d9f8-x2x9q"})
+	anonymizer.Learn("event", map[string]interface{}{
+		"metadata":  map[string]interface{}{"name": "web.17a", "namespace": "shop"},
+		"regarding": map[string]interface{}{"kind": "Pod", "namespace": "shop", "name": "web-5c6d7-abcde"},
+	})
+
+	assert.True(t, strings.HasPrefix(anonymizer.Text("payments"), "ns-"))
+	assert.True(t, strings.HasPrefix(anonymizer.Text("api-7d9f8-x2x9q"), "pod-"))
+	assert.True(t, strings.HasPrefix(anonymizer.Text("shop"), "ns-"))
+	assert.True(t, strings.HasPrefix(anonymizer.Text("web-5c6d7-abcde"), "pod-"))
+}
+
+func TestDeanonymize(t *testing.T) {
+	anonymizer := newTestAnonymizer()
+	original := "pod api-7d9f8-x2x9q in payments on ip-10-0-1-5.ec2.internal talks to 10.0.3.7"
+
+	anonymized := anonymizer.Text(original)
+	assert.NotEqual(t, original, anonymized)
+
+	assert.Equal(t, original, Deanonymize(anonymized, anonymizer.Mapping()))
+}
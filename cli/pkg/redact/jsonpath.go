@@ -0,0 +1,113 @@
+package redact
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pathSegment is one step of a parsed JSONPath, either a field name, a list
+// index or a wildcard matching every field or item
+type pathSegment struct {
+	field    string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parsePath parses the subset of JSONPath used by redaction rules: field
+// access with dots or quoted brackets, list indexes and wildcards, for
+// example $.spec.containers[*].args or {.data['config.yaml']}
+func parsePath(path string) ([]pathSegment, error) {
+	expr := strings.TrimSpace(path)
+	if strings.HasPrefix(expr, "{") && strings.HasSuffix(expr, "}") {
+		expr = expr[1 : len(expr)-1]
+	}
+	expr = strings.TrimPrefix(expr, "$")
+
+	var segments []pathSegment
+	for expr != "" {
+		switch expr[0] {
+		case '.':
+			expr = expr[1:]
+			end := strings.IndexAny(expr, ".[")
+			if end == -1 {
+				end = len(expr)
+			}
+			field := expr[:end]
+			if field == "" {
+				return nil, fmt.Errorf("invalid path %q: empty field name", path)
+			}
+			expr = expr[end:]
+
+			if field == "*" {
+				segments = append(segments, pathSegment{wildcard: true})
+			} else {
+				segments = append(segments, pathSegment{field: field})
+			}
+		case '[':
+			end := strings.Index(expr, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("invalid path %q: unclosed bracket", path)
+			}
+			selector := expr[1:end]
+			expr = expr[end+1:]
+
+			switch {
+			case selector == "*":
+				segments = append(segments, pathSegment{wildcard: true})
+			case len(selector) >= 2 && (selector[0] == '\'' || selector[0] == '"') && selector[len(selector)-1] == selector[0]:
+				segments = append(segments, pathSegment{field: selector[1 : len(selector)-1]})
+			default:
+				index, err := strconv.Atoi(selector)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("invalid path %q: unsupported selector [%s]", path, selector)
+				}
+				segments = append(segments, pathSegment{index: index, isIndex: true})
+			}
+		default:
+			return nil, fmt.Errorf("invalid path %q: expected . or [ at %q", path, expr)
+		}
+	}
+
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("invalid path %q: no fields", path)
+	}
+
+	return segments, nil
+}
+
+// applyPath replaces every value matched by the path with the result of fn
+// and returns the total count fn reported
+func applyPath(node interface{}, path []pathSegment, fn func(interface{}) (interface{}, int)) (interface{}, int) {
+	if len(path) == 0 {
+		return fn(node)
+	}
+
+	segment, rest := path[0], path[1:]
+	count := 0
+
+	switch typed := node.(type) {
+	case map[string]interface{}:
+		if segment.isIndex {
+			return node, 0
+		}
+		for key, value := range typed {
+			if segment.wildcard || key == segment.field {
+				var n int
+				typed[key], n = applyPath(value, rest, fn)
+				count += n
+			}
+		}
+	case []interface{}:
+		for i, value := range typed {
+			if segment.wildcard || (segment.isIndex && segment.index == i) {
+				var n int
+				typed[i], n = applyPath(value, rest, fn)
+				count += n
+			}
+		}
+	}
+
+	return node, count
+}
@@ -0,0 +1,66 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected []pathSegment
+	}{
+		{
+			path:     "$.spec.containers[*].args",
+			expected: []pathSegment{{field: "spec"}, {field: "containers"}, {wildcard: true}, {field: "args"}},
+		},
+		{
+			path:     "{.data['config.yaml']}",
+			expected: []pathSegment{{field: "data"}, {field: "config.yaml"}},
+		},
+		{
+			path:     ".items[2].*",
+			expected: []pathSegment{{field: "items"}, {index: 2, isIndex: true}, {wildcard: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			segments, err := parsePath(tt.path)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, segments)
+		})
+	}
+}
+
+func TestParsePath_Invalid(t *testing.T) {
+	for _, path := range []string{"", "$", "spec", "$.spec[", "$.spec[-1]", "$..spec"} {
+		_, err := parsePath(path)
+		assert.Error(t, err, path)
+	}
+}
+
+func TestApplyPath(t *testing.T) {
+	data := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "a", "args": []interface{}{"--x"}},
+				map[string]interface{}{"name": "b"},
+			},
+		},
+	}
+
+	path, err := parsePath("$.spec.containers[*].name")
+	assert.NoError(t, err)
+
+	_, count := applyPath(data, path, func(value interface{}) (interface{}, int) {
+		return Placeholder, 1
+	})
+
+	assert.Equal(t, 2, count)
+	containers := data["spec"].(map[string]interface{})["containers"].([]interface{})
+	assert.Equal(t, Placeholder, containers[0].(map[string]interface{})["name"])
+	assert.Equal(t, Placeholder, containers[1].(map[string]interface{})["name"])
+	assert.Equal(t, []interface{}{"--x"}, containers[0].(map[string]interface{})["args"])
+}
@@ -0,0 +1,118 @@
+// Package redact removes sensitive values from collected resources
+package redact
+
+import (
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Placeholder replaces every redacted value
+const Placeholder = "[REDACTED]"
+
+var (
+	secretNamePattern = regexp.MustCompile(`(?i)(passw(or)?d|passwd|secret|token|api[_-]?key|private[_-]?key|credential)`)
+	// authWordPattern only matches auth as a word of its own, as in
+	// basic-auth, authToken, proxyAuth or AUTH_HEADER, so keys such as
+	// authorizationMode, oauth2-proxy or authority are kept
+	authWordPattern = regexp.MustCompile(`(^|[^A-Za-z])[Aa]uth([^a-z]|$)|[a-z0-9]Auth([^a-z]|$)|(^|[^A-Za-z])AUTH([^A-Z]|$)`)
+)
+
+// IsSecretName reports whether a key or variable name suggests that its
+// value is a credential
+func IsSecretName(name string) bool {
+	return secretNamePattern.MatchString(name) || authWordPattern.MatchString(name)
+}
+
+// Values returns a copy of a values tree with every scalar below a secret
+// like key replaced by the placeholder
+func Values(values map[string]interface{}) map[string]interface{} {
+	if values == nil {
+		return nil
+	}
+
+	redacted := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		if IsSecretName(key) {
+			redacted[key] = redactAll(value)
+			continue
+		}
+		redacted[key] = redactValue(value)
+	}
+
+	return redacted
+}
+
+func redactValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		return Values(typed)
+	case []interface{}:
+		items := make([]interface{}, len(typed))
+		for i, item := range typed {
+			items[i] = redactValue(item)
+		}
+		return items
+	default:
+		return value
+	}
+}
+
+// redactAll replaces every scalar of a value, keeping its structure
+func redactAll(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			redacted[key] = redactAll(item)
+		}
+		return redacted
+	case []interface{}:
+		items := make([]interface{}, len(typed))
+		for i, item := range typed {
+			items[i] = redactAll(item)
+		}
+		return items
+	case nil:
+		return nil
+	default:
+		return Placeholder
+	}
+}
+
+// Manifest redacts the data of every Secret in a multi document YAML
+// manifest, other documents are kept as they are
+func Manifest(manifest string) string {
+	documents := strings.Split(manifest, "\n---")
+	for i, document := range documents {
+		var object map[string]interface{}
+		if err := yaml.Unmarshal([]byte(document), &object); err != nil || object["kind"] != "Secret" {
+			continue
+		}
+
+		for _, field := range []string{"data", "stringData"} {
+			if data, ok := object[field].(map[string]interface{}); ok {
+				object[field] = redactAll(data)
+			}
+		}
+
+		redacted, err := yaml.Marshal(object)
+		if err != nil {
+			redacted = []byte(Placeholder + "\n")
+		}
+
+		// Keep the separator and the source comment helm puts above every
+		// document
+		var header []string
+		for _, line := range strings.Split(document, "\n") {
+			if line != "" && line != "---" && !strings.HasPrefix(line, "#") {
+				break
+			}
+			header = append(header, line)
+		}
+		documents[i] = strings.Join(append(header, string(redacted)), "\n")
+	}
+
+	return strings.Join(documents, "\n---")
+}
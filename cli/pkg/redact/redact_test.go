@@ -0,0 +1,83 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSecretName(t *testing.T) {
+	assert.True(t, IsSecretName("DB_PASSWORD"))
+	assert.True(t, IsSecretName("apiKey"))
+	assert.True(t, IsSecretName("GITHUB_TOKEN"))
+	assert.True(t, IsSecretName("clientSecret"))
+	assert.False(t, IsSecretName("replicaCount"))
+	assert.False(t, IsSecretName("LOG_LEVEL"))
+
+	// auth only counts as a word of its own
+	for _, name := range []string{"auth", "basic-auth", "authToken", "proxyAuth", "AUTH_HEADER", "BASIC_AUTH", "x.auth"} {
+		assert.True(t, IsSecretName(name), name)
+	}
+	for _, name := range []string{"authorizationMode", "oauth2-proxy", "OAUTH2_PROVIDER", "authority", "OAuthProvider", "authenticationMethod"} {
+		assert.False(t, IsSecretName(name), name)
+	}
+}
+
+func TestValues(t *testing.T) {
+	values := map[string]interface{}{
+		"replicaCount": 3,
+		"image":        map[string]interface{}{"tag": "1.0"},
+		"database": map[string]interface{}{
+			"host":     "db",
+			"password": "hunter2",
+		},
+		"credentials": map[string]interface{}{
+			"user": "admin",
+			"keys": []interface{}{"a", "b"},
+		},
+		"extraEnv": []interface{}{
+			map[string]interface{}{"name": "A", "apiToken": "t0k3n"},
+		},
+	}
+
+	redacted := Values(values)
+
+	assert.Equal(t, 3, redacted["replicaCount"])
+	assert.Equal(t, map[string]interface{}{"tag": "1.0"}, redacted["image"])
+	assert.Equal(t, map[string]interface{}{"host": "db", "password": Placeholder}, redacted["database"])
+	assert.Equal(t, map[string]interface{}{
+		"user": Placeholder,
+		"keys": []interface{}{Placeholder, Placeholder},
+	}, redacted["credentials"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "A", "apiToken": Placeholder}}, redacted["extraEnv"])
+
+	// The input is left untouched
+	assert.Equal(t, "hunter2", values["database"].(map[string]interface{})["password"])
+}
+
+func TestManifest(t *testing.T) {
+	manifest := `---
+# Source: app/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+data:
+  password: visible-in-configmap
+---
+# Source: app/templates/secret.yaml
+apiVersion: v1
+kind: Secret
+metadata:
+  name: app
+stringData:
+  password: hunter2
+`
+
+	redacted := Manifest(manifest)
+
+	assert.Contains(t, redacted, "visible-in-configmap")
+	assert.NotContains(t, redacted, "hunter2")
+	assert.Contains(t, redacted, "password: '[REDACTED]'")
+	assert.Contains(t, redacted, "# Source: app/templates/secret.yaml")
+}
@@ -0,0 +1,240 @@
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// Rule is a user defined redaction rule. A rule with only a JSONPath
+// replaces the values at that path, a rule with only a regex replaces its
+// matches in every string and a rule with both replaces the matches in the
+// values at the path
+type Rule struct {
+	Name string `json:"name"`
+	// Kinds limits the rule to resources of these kinds, all kinds when empty
+	Kinds    []string `json:"kinds,omitempty"`
+	JSONPath string   `json:"jsonPath,omitempty"`
+	Regex    string   `json:"regex,omitempty"`
+}
+
+// Hits counts the values replaced by each rule
+type Hits map[string]int
+
+// rule is a compiled redaction rule, built in or user defined
+type rule struct {
+	name  string
+	kinds []string
+	apply func(data interface{}) (interface{}, int)
+}
+
+// LastAppliedAnnotation holds the full object as applied by kubectl, for
+// secrets that includes every value in clear text
+const LastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+var (
+	bearerTokenPattern = regexp.MustCompile(`(?i)\b(bearer)\s+[A-Za-z0-9\-._~+/]+=*`)
+	privateKeyPattern  = regexp.MustCompile(`-----BEGIN ([A-Z0-9 ]*)PRIVATE KEY-----[\s\S]*?-----END ([A-Z0-9 ]*)PRIVATE KEY-----`)
+)
+
+var builtinRules = []rule{
+	{name: "secret-env", apply: redactSecretEnv},
+	{name: "last-applied-configuration", apply: redactLastApplied},
+	{
+		name: "bearer-token",
+		apply: func(data interface{}) (interface{}, int) {
+			return replaceStrings(data, bearerTokenPattern, "$1 "+Placeholder)
+		},
+	},
+	{
+		name: "private-key",
+		apply: func(data interface{}) (interface{}, int) {
+			return replaceStrings(data, privateKeyPattern, Placeholder)
+		},
+	},
+}
+
+// Redactor removes sensitive values from resources before they are
+// persisted, using the built in rules followed by the user rules
+type Redactor struct {
+	rules []rule
+}
+
+func NewRedactor(rules []Rule) (*Redactor, error) {
+	redactor := &Redactor{rules: slices.Clone(builtinRules)}
+
+	for i, userRule := range rules {
+		compiled, err := compileRule(userRule)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile redaction rule %d: %w", i, err)
+		}
+		if compiled.name == "" {
+			compiled.name = fmt.Sprintf("custom-%d", i)
+		}
+		redactor.rules = append(redactor.rules, compiled)
+	}
+
+	return redactor, nil
+}
+
+// Redact returns a redacted copy of the data of a resource, the data itself
+// is never modified
+func (r *Redactor) Redact(kind string, data interface{}) (interface{}, Hits, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal resource: %w", err)
+	}
+
+	var redacted interface{}
+	if err := json.Unmarshal(raw, &redacted); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal resource: %w", err)
+	}
+
+	hits := Hits{}
+	for _, rule := range r.rules {
+		if len(rule.kinds) > 0 && !slices.Contains(rule.kinds, kind) {
+			continue
+		}
+
+		var count int
+		redacted, count = rule.apply(redacted)
+		if count > 0 {
+			hits[rule.name] += count
+		}
+	}
+
+	return redacted, hits, nil
+}
+
+func compileRule(userRule Rule) (rule, error) {
+	compiled := rule{name: userRule.Name, kinds: userRule.Kinds}
+
+	var pattern *regexp.Regexp
+	if userRule.Regex != "" {
+		var err error
+		pattern, err = regexp.Compile(userRule.Regex)
+		if err != nil {
+			return compiled, fmt.Errorf("invalid regex: %w", err)
+		}
+	}
+
+	replace := func(value interface{}) (interface{}, int) {
+		if pattern == nil {
+			if value == nil {
+				return nil, 0
+			}
+			return redactAll(value), 1
+		}
+		return replaceStrings(value, pattern, Placeholder)
+	}
+
+	switch {
+	case userRule.JSONPath != "":
+		path, err := parsePath(userRule.JSONPath)
+		if err != nil {
+			return compiled, err
+		}
+		compiled.apply = func(data interface{}) (interface{}, int) {
+			return applyPath(data, path, replace)
+		}
+	case pattern != nil:
+		compiled.apply = replace
+	default:
+		return compiled, fmt.Errorf("rule needs a jsonPath or a regex")
+	}
+
+	return compiled, nil
+}
+
+// replaceStrings replaces the matches of a pattern in every string of a
+// value and returns the number of matches replaced
+func replaceStrings(value interface{}, pattern *regexp.Regexp, replacement string) (interface{}, int) {
+	switch typed := value.(type) {
+	case string:
+		matches := len(pattern.FindAllStringIndex(typed, -1))
+		if matches == 0 {
+			return value, 0
+		}
+		return pattern.ReplaceAllString(typed, replacement), matches
+	case map[string]interface{}:
+		count := 0
+		for key, item := range typed {
+			var n int
+			typed[key], n = replaceStrings(item, pattern, replacement)
+			count += n
+		}
+		return typed, count
+	case []interface{}:
+		count := 0
+		for i, item := range typed {
+			var n int
+			typed[i], n = replaceStrings(item, pattern, replacement)
+			count += n
+		}
+		return typed, count
+	default:
+		return value, 0
+	}
+}
+
+// redactSecretEnv replaces the value of every container environment
+// variable with a secret like name
+func redactSecretEnv(data interface{}) (interface{}, int) {
+	count := 0
+	walk(data, func(node map[string]interface{}) {
+		env, ok := node["env"].([]interface{})
+		if !ok {
+			return
+		}
+
+		for _, item := range env {
+			variable, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := variable["name"].(string)
+			if value, ok := variable["value"].(string); ok && value != "" && IsSecretName(name) {
+				variable["value"] = Placeholder
+				count++
+			}
+		}
+	})
+
+	return data, count
+}
+
+// redactLastApplied replaces the kubectl last applied configuration
+// annotation, which repeats the whole object including values hidden
+// elsewhere
+func redactLastApplied(data interface{}) (interface{}, int) {
+	count := 0
+	walk(data, func(node map[string]interface{}) {
+		annotations, ok := node["annotations"].(map[string]interface{})
+		if !ok {
+			return
+		}
+
+		if _, exists := annotations[LastAppliedAnnotation]; exists {
+			annotations[LastAppliedAnnotation] = Placeholder
+			count++
+		}
+	})
+
+	return data, count
+}
+
+// walk calls fn for every object nested in a value
+func walk(value interface{}, fn func(node map[string]interface{})) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		fn(typed)
+		for _, child := range typed {
+			walk(child, fn)
+		}
+	case []interface{}:
+		for _, child := range typed {
+			walk(child, fn)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package redact
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Report lists which rules replaced how many values, it never contains the
+// values themselves
+type Report struct {
+	Resources []ReportEntry `json:"resources"`
+	Logs      []ReportEntry `json:"logs"`
+	// Pods sums the log replacements of every pod, keyed by namespace/pod
+	Pods   map[string]Hits `json:"pods"`
+	Totals Hits            `json:"totals"`
+}
+
+type ReportEntry struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Rules     Hits   `json:"rules"`
+}
+
+func NewReport() *Report {
+	return &Report{
+		Resources: []ReportEntry{},
+		Logs:      []ReportEntry{},
+		Pods:      map[string]Hits{},
+		Totals:    Hits{},
+	}
+}
+
+// AddResource records the rules that fired for a resource, resources
+// without replacements are left out
+func (r *Report) AddResource(kind string, namespace string, name string, hits Hits) {
+	if len(hits) == 0 {
+		return
+	}
+
+	r.Resources = append(r.Resources, ReportEntry{Kind: kind, Namespace: namespace, Name: name, Rules: hits})
+	r.addTotals(hits)
+}
+
+// AddLog records the detectors that fired for a log file of a pod
+func (r *Report) AddLog(namespace string, pod string, name string, hits Hits) {
+	if len(hits) == 0 {
+		return
+	}
+
+	r.Logs = append(r.Logs, ReportEntry{Kind: "log", Namespace: namespace, Name: name, Rules: hits})
+
+	key := namespace + "/" + pod
+	if r.Pods[key] == nil {
+		r.Pods[key] = Hits{}
+	}
+	for rule, count := range hits {
+		r.Pods[key][rule] += count
+	}
+	r.addTotals(hits)
+}
+
+func (r *Report) addTotals(hits Hits) {
+	for rule, count := range hits {
+		r.Totals[rule] += count
+	}
+}
+
+// Summary describes the report in a few lines for the terminal
+func (r *Report) Summary() string {
+	if len(r.Totals) == 0 {
+		return "Redaction: no values replaced\n"
+	}
+
+	total := 0
+	rules := make([]string, 0, len(r.Totals))
+	for rule, count := range r.Totals {
+		total += count
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "Redaction: replaced %d values in %d resources and %d log files\n", total, len(r.Resources), len(r.Logs))
+	for _, rule := range rules {
+		fmt.Fprintf(&summary, "  %-28s %d\n", rule, r.Totals[rule])
+	}
+
+	return summary.String()
+}
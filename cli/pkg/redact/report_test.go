@@ -0,0 +1,58 @@
+package redact
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReport_Add(t *testing.T) {
+	report := NewReport()
+
+	report.AddResource("pod", "default", "web", Hits{"secret-env": 2})
+	report.AddResource("configmap", "default", "app", Hits{})
+	report.AddLog("default", "web", "web/app.log", Hits{"email": 3, "jwt": 1})
+	report.AddLog("default", "web", "web/app.previous.log", Hits{"email": 1})
+
+	assert.Len(t, report.Resources, 1)
+	assert.Equal(t, ReportEntry{Kind: "pod", Namespace: "default", Name: "web", Rules: Hits{"secret-env": 2}}, report.Resources[0])
+	assert.Len(t, report.Logs, 2)
+	assert.Equal(t, map[string]Hits{"default/web": {"email": 4, "jwt": 1}}, report.Pods)
+	assert.Equal(t, Hits{"secret-env": 2, "email": 4, "jwt": 1}, report.Totals)
+}
+
+func TestReport_NeverContainsValues(t *testing.T) {
+	redactor, err := NewRedactor(nil)
+	assert.NoError(t, err)
+
+	_, hits, err := redactor.Redact("pod", map[string]interface{}{
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"env": []interface{}{map[string]interface{}{"name": "DB_PASSWORD", "value": "hunter2"}}},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	report := NewReport()
+	report.AddResource("pod", "default", "web", hits)
+
+	raw, err := json.Marshal(report)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(raw), "hunter2")
+	assert.Contains(t, string(raw), `"secret-env":1`)
+}
+
+func TestReport_Summary(t *testing.T) {
+	report := NewReport()
+	assert.Equal(t, "Redaction: no values replaced\n", report.Summary())
+
+	report.AddResource("pod", "default", "web", Hits{"secret-env": 2})
+	report.AddLog("default", "web", "web/app.log", Hits{"email": 3})
+
+	summary := report.Summary()
+	assert.Contains(t, summary, "replaced 5 values in 1 resources and 1 log files")
+	assert.Regexp(t, `email\s+3`, summary)
+	assert.Regexp(t, `secret-env\s+2`, summary)
+}
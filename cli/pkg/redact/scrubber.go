@@ -0,0 +1,161 @@
+package redact
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+)
+
+// LogRule is a user defined log scrubbing rule, matches of the regex are
+// replaced with a placeholder derived from the rule name
+type LogRule struct {
+	Name  string `json:"name"`
+	Regex string `json:"regex"`
+}
+
+// detector finds one kind of sensitive value in a log line
+type detector struct {
+	name        string
+	pattern     *regexp.Regexp
+	replacement string
+	// valid filters out matches of a loose pattern, nil accepts every match
+	valid func(match string) bool
+}
+
+// builtinDetectors run in order, credentials embedded in URLs are replaced
+// before emails so user:password@host is not mistaken for an address
+var builtinDetectors = []detector{
+	{
+		name:        "jwt",
+		pattern:     regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`),
+		replacement: "[JWT]",
+	},
+	{
+		name:        "aws-access-key",
+		pattern:     regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+		replacement: "[AWS_ACCESS_KEY]",
+	},
+	{
+		name:        "aws-secret-key",
+		pattern:     regexp.MustCompile(`(?i)(aws_?secret_?(?:access_?)?key["']?\s*[:=]\s*["']?)[A-Za-z0-9/+=]{40}`),
+		replacement: "${1}[AWS_SECRET_KEY]",
+	},
+	{
+		name:        "password",
+		pattern:     regexp.MustCompile(`(://[^:/\s@]+:)[^@\s/]+@`),
+		replacement: "${1}[PASSWORD]@",
+	},
+	{
+		name:        "password",
+		pattern:     regexp.MustCompile(`(?i)\b((?:passw(?:or)?d|pwd|secret|token|api[_-]?key)["']?\s*[:=]\s*["']?)[^\s"'&,;]+`),
+		replacement: "${1}[PASSWORD]",
+	},
+	{
+		name:        "email",
+		pattern:     regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+		replacement: "[EMAIL]",
+	},
+	{
+		name:        "ipv6",
+		pattern:     regexp.MustCompile(`(?i)[0-9a-f]{0,4}(?::[0-9a-f]{0,4}){2,7}`),
+		replacement: "[IPV6]",
+		// Requiring three groups keeps "::" in code identifiers and
+		// loopback addresses out
+		valid: func(match string) bool {
+			groups := 0
+			for _, group := range strings.Split(match, ":") {
+				if group != "" {
+					groups++
+				}
+			}
+			return groups >= 3 && net.ParseIP(match) != nil
+		},
+	},
+	{
+		name:        "ipv4",
+		pattern:     regexp.MustCompile(`(?:\b(?i:v|version)[\s:=]*)?\b(?:(?:25[0-5]|2[0-4][0-9]|1[0-9][0-9]|[1-9]?[0-9])\.){3}(?:25[0-5]|2[0-4][0-9]|1[0-9][0-9]|[1-9]?[0-9])\b`),
+		replacement: "[IPV4]",
+		// Four part versions such as v1.2.3.4 or version 1.2.3.4 look like
+		// addresses, the pattern takes their prefix along to tell them apart
+		valid: func(match string) bool {
+			return match[0] >= '0' && match[0] <= '9'
+		},
+	},
+}
+
+// Scrubber replaces credentials and personal data in log content with
+// typed placeholders such as [JWT] or [EMAIL]
+type Scrubber struct {
+	detectors []detector
+}
+
+func NewScrubber(rules []LogRule) (*Scrubber, error) {
+	scrubber := &Scrubber{detectors: append([]detector{}, builtinDetectors...)}
+
+	for i, rule := range rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("log rule %d has no name", i)
+		}
+
+		pattern, err := regexp.Compile(rule.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile log rule %s: %w", rule.Name, err)
+		}
+
+		scrubber.detectors = append(scrubber.detectors, detector{
+			name:        rule.Name,
+			pattern:     pattern,
+			replacement: "[" + strings.ToUpper(strings.ReplaceAll(rule.Name, "-", "_")) + "]",
+		})
+	}
+
+	return scrubber, nil
+}
+
+// Scrub copies log content from r to w line by line, replacing every
+// detected value, and returns the number of replacements per detector
+func (s *Scrubber) Scrub(r io.Reader, w io.Writer) (Hits, error) {
+	hits := Hits{}
+	reader := bufio.NewReader(r)
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return hits, fmt.Errorf("failed to read log: %w", err)
+		}
+
+		if line != "" {
+			if _, writeErr := io.WriteString(w, s.scrubLine(line, hits)); writeErr != nil {
+				return hits, fmt.Errorf("failed to write log: %w", writeErr)
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return hits, nil
+		}
+	}
+}
+
+func (s *Scrubber) scrubLine(line string, hits Hits) string {
+	for _, detector := range s.detectors {
+		line = detector.pattern.ReplaceAllStringFunc(line, func(match string) string {
+			if detector.valid != nil && !detector.valid(match) {
+				return match
+			}
+			hits[detector.name]++
+
+			// Expand the capture groups of the replacement for this match
+			submatches := detector.pattern.FindStringSubmatchIndex(match)
+			if submatches == nil {
+				return detector.replacement
+			}
+			return string(detector.pattern.ExpandString(nil, detector.replacement, match, submatches))
+		})
+	}
+
+	return line
+}
@@ -2,53 +2,611 @@
 package snapshot
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
 
 	"github.com/3nd3r1/kubin/cli/pkg/collector"
+	"github.com/3nd3r1/kubin/cli/pkg/config"
 	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	"github.com/3nd3r1/kubin/cli/pkg/log"
 	"github.com/3nd3r1/kubin/cli/pkg/persister"
+	"github.com/3nd3r1/kubin/cli/pkg/redact"
 )
 
+// Options controls what ends up in a snapshot
+type Options struct {
+	// Kubeconfig and Context select the cluster like kubectl's flags of the
+	// same name, the in-cluster service account is used without a kubeconfig
+	Kubeconfig string
+	Context    string
+
+	// As and AsGroups impersonate a user and its groups for every request
+	As       string
+	AsGroups []string
+
+	// EventsSince only captures events seen within the window, zero captures
+	// every event
+	EventsSince time.Duration
+
+	// LogTailLines, LogSince and LogLimitBytes limit the collected log of
+	// each container, zero means no limit
+	LogTailLines  int64
+	LogSince      time.Duration
+	LogLimitBytes int64
+
+	// IncludeSecretValues captures secret values in clear text instead of
+	// only their keys, sizes and hashes
+	IncludeSecretValues bool
+
+	// Anonymize replaces namespace, pod, node and host names and internal
+	// IPs with keyed hashes, the mapping back is kept locally
+	Anonymize bool
+
+	// Scope limits the snapshot to some namespaces and to the objects
+	// matching its selectors, cluster scoped resources are always captured
+	Scope kube.Scope
+
+	// Kinds selects the captured kinds, the config file is used when it
+	// is empty
+	Kinds collector.KindFilter
+
+	// Concurrency is how many calls to the API server are made at once,
+	// across all collectors
+	Concurrency int
+
+	// PageSize is how many objects each list call reads at once, zero
+	// reads every list in one call
+	PageSize int64
+
+	// KubeQPS and KubeBurst limit the requests per second sent to the API
+	// server, on top of Concurrency
+	KubeQPS   float32
+	KubeBurst int
+
+	// Timeout bounds the whole capture, zero means no deadline
+	Timeout time.Duration
+
+	// CollectorTimeout bounds every collector unless the config file sets
+	// a timeout for it, zero means no deadline
+	CollectorTimeout time.Duration
+}
+
+// collectorGracePeriod is how long a collector may take after its deadline
+// to return what it collected before it is abandoned
+const collectorGracePeriod = 10 * time.Second
+
 type Manager struct {
-	collectors []collector.Collector
-	persister  persister.Persister
+	clusterInfo *collector.ClusterInfoCollector
+	collectors  []collector.Collector
+	scoped      *kube.ScopedClient
+	redactor    *redact.Redactor
+	scrubber    *redact.Scrubber
+	report      *redact.Report
+	anonymizer  *redact.Anonymizer
+	persister   persister.Persister
+	kinds       collector.KindFilter
+	manifest    Manifest
+	failures    collector.Failures
+
+	concurrency       int
+	timeout           time.Duration
+	collectorTimeouts map[string]time.Duration
+	collectorTimeout  time.Duration
 }
 
-func NewManager() (*Manager, error) {
-	mgr := &Manager{}
+func NewManager(opts Options) (*Manager, error) {
+	mgr := &Manager{
+		concurrency:       opts.Concurrency,
+		timeout:           opts.Timeout,
+		collectorTimeout:  opts.CollectorTimeout,
+		collectorTimeouts: make(map[string]time.Duration),
+	}
+
+	if err := opts.Scope.Validate(); err != nil {
+		return nil, err
+	}
+
+	if opts.Concurrency < 1 {
+		return nil, fmt.Errorf("concurrency must be at least 1, got %d", opts.Concurrency)
+	}
+
+	if opts.Timeout < 0 || opts.CollectorTimeout < 0 {
+		return nil, fmt.Errorf("timeouts must not be negative")
+	}
 
-	kubeClient, err := kube.NewKubeClient()
+	kubeClient, err := kube.NewKubeClient(kube.ClientOptions{
+		Kubeconfig: opts.Kubeconfig,
+		Context:    opts.Context,
+		As:         opts.As,
+		AsGroups:   opts.AsGroups,
+		PageSize:   opts.PageSize,
+		OnPage:     logPage,
+		QPS:        opts.KubeQPS,
+		Burst:      opts.KubeBurst,
+	})
 	if err != nil {
 		return nil, err
 	}
+	limitedClient := kube.NewLimitedClient(kubeClient, opts.Concurrency)
+	scopedClient := kube.NewScopedClient(limitedClient, opts.Scope)
+	mgr.scoped = scopedClient
 
-	mgr.collectors = []collector.Collector{
-		collector.NewCoreCollector(kubeClient),
+	secretSalt, err := config.LoadKey("secret-salt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load secret salt: %w", err)
 	}
 
-	mgr.persister, err = persister.NewTarGzPersister()
+	fileConfig, err := config.LoadFile()
 	if err != nil {
 		return nil, err
 	}
 
+	mgr.kinds = opts.Kinds
+	if mgr.kinds.IsEmpty() {
+		mgr.kinds = collector.KindFilter{Include: fileConfig.Kinds.Include, Exclude: fileConfig.Kinds.Exclude}
+	}
+	if err := mgr.kinds.Validate(); err != nil {
+		return nil, err
+	}
+
+	mgr.manifest = Manifest{
+		Partial: opts.Scope.IsPartial() || !mgr.kinds.IsEmpty(),
+		Scope:   opts.Scope,
+		Kinds:   mgr.kinds,
+	}
+
+	mgr.redactor, err = redact.NewRedactor(fileConfig.Redaction.Rules)
+	if err != nil {
+		return nil, err
+	}
+
+	mgr.scrubber, err = redact.NewScrubber(fileConfig.Redaction.LogRules)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Anonymize {
+		key, err := config.LoadKey("anonymization-key")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load anonymization key: %w", err)
+		}
+		mgr.anonymizer = redact.NewAnonymizer(key)
+	}
+
+	mgr.clusterInfo = collector.NewClusterInfoCollector(limitedClient)
+	// The nodes collector sums the requests of every pod on a node, so it
+	// keeps seeing pods outside the scope. Helm release secrets never carry
+	// the labels of the app, they are found by a selector of their own, and
+	// the permissions of service accounts need every role and binding
+	mgr.collectors = []collector.Collector{
+		collector.NewCoreCollector(scopedClient, mgr.kinds),
+		collector.NewWorkloadsCollector(scopedClient, mgr.kinds),
+		collector.NewNetworkingCollector(scopedClient, mgr.kinds),
+		collector.NewStorageCollector(scopedClient, mgr.kinds),
+		collector.NewNodesCollector(limitedClient, mgr.kinds),
+		collector.NewMetricsCollector(scopedClient, mgr.kinds),
+		collector.NewRBACCollector(scopedClient.WithoutSelectors(), mgr.kinds),
+		collector.NewHelmCollector(scopedClient.WithoutSelectors()),
+		collector.NewConfigCollector(scopedClient, mgr.kinds, collector.ConfigOptions{
+			IncludeSecretValues: opts.IncludeSecretValues,
+			SecretSalt:          secretSalt,
+		}),
+		collector.NewEventsCollector(scopedClient, opts.EventsSince),
+		collector.NewLogsCollector(scopedClient, collector.LogOptions{
+			TailLines:  opts.LogTailLines,
+			Since:      opts.LogSince,
+			LimitBytes: opts.LogLimitBytes,
+		}),
+		collector.NewDynamicCollector(scopedClient, mgr.kinds),
+	}
+
+	for name, timeout := range fileConfig.Timeouts.Collectors {
+		if !mgr.hasCollector(name) {
+			return nil, fmt.Errorf("unknown collector %q in timeouts", name)
+		}
+		if timeout.Duration < 0 {
+			return nil, fmt.Errorf("timeout of collector %s must not be negative", name)
+		}
+		mgr.collectorTimeouts[name] = timeout.Duration
+	}
+
 	return mgr, nil
 }
 
+func (mgr *Manager) hasCollector(name string) bool {
+	for _, c := range mgr.collectors {
+		if c.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// timeoutOf returns the time a collector is given, zero means no deadline
+func (mgr *Manager) timeoutOf(c collector.Collector) time.Duration {
+	if timeout, exists := mgr.collectorTimeouts[c.Name()]; exists {
+		return timeout
+	}
+	return mgr.collectorTimeout
+}
+
+// ListKinds returns every kind that can be captured from the cluster
+func (mgr *Manager) ListKinds(ctx context.Context) ([]collector.KindInfo, error) {
+	return collector.ListKinds(ctx, mgr.collectors)
+}
+
+// Captures reports whether a kind is selected by the kind filter
+func (mgr *Manager) Captures(kind collector.KindInfo) bool {
+	return mgr.kinds.Matches(kind.Collector, kind.Kind)
+}
+
 func (mgr *Manager) CreateSnapshot(ctx context.Context) error {
+	// Whatever was collected when the deadline passes is still written
+	if mgr.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, mgr.timeout)
+		defer cancel()
+	}
+
+	mgr.failures = collector.Failures{}
+
+	// cluster.json is written from whatever could be read, the nodes
+	// captured there do not depend on the kind filter
+	clusterInfo, err := mgr.clusterInfo.Collect(ctx)
+	var failures collector.Failures
+	if err != nil && !errors.As(err, &failures) {
+		return err
+	}
+	for _, failure := range failures {
+		mgr.addFailure(failure)
+	}
+
+	mgr.persister, err = persister.NewTarGzPersister()
+	if err != nil {
+		return err
+	}
+
+	if mgr.anonymizer != nil {
+		mgr.anonymizer.AddIdentifier(redact.PrefixCluster, clusterInfo.Context)
+		mgr.anonymizer.AddIdentifier(redact.PrefixCluster, clusterInfo.ClusterName)
+		if server, err := url.Parse(clusterInfo.Server); err == nil && net.ParseIP(server.Hostname()) == nil {
+			mgr.anonymizer.AddIdentifier(redact.PrefixHost, server.Hostname())
+		}
+		for _, node := range clusterInfo.Nodes {
+			mgr.anonymizer.AddIdentifier(redact.PrefixNode, node.Name)
+		}
+	}
+
+	mgr.manifest.CreatedAt = clusterInfo.CollectedAt
+	mgr.report = redact.NewReport()
+
+	// Collectors that fail to find the related objects record it themselves
+	if err := mgr.scoped.ResolveRelated(ctx); err != nil {
+		log.WithError(err).Warnw("Failed to find the objects related to the selected pods")
+	}
+
+	// Nothing is persisted before every collector is done, a log or event
+	// may name a pod or node that no captured resource does and every
+	// identifier has to be known before the first one is anonymized
+	collected, err := mgr.collect(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := mgr.persistFile("cluster.json", clusterInfo); err != nil {
+		return err
+	}
+
+	for _, captured := range collected {
+		for _, resource := range captured.resources {
+			resource, err := mgr.anonymize(resource)
+			if err != nil {
+				return err
+			}
+
+			if err := mgr.persister.Persist(resource); err != nil {
+				log.WithError(err).Warnw("Failed to persist resource", "kind", resource.Kind, "name", resource.Name)
+				mgr.failures = append(mgr.failures, &collector.Failure{
+					Collector: captured.collector,
+					Kind:      resource.Kind,
+					Namespace: resource.Metadata["namespace"],
+					Name:      resource.Name,
+					Reason:    "PersistFailed",
+					Message:   err.Error(),
+				})
+			}
+		}
+	}
+
+	if err := mgr.persistFile("errors.json", mgr.failures); err != nil {
+		return err
+	}
+	mgr.manifest.Incomplete = len(mgr.failures) > 0
+
+	if err := mgr.persistFile("redaction-report.json", mgr.report); err != nil {
+		return err
+	}
+
+	if err := mgr.persistFile("manifest.json", mgr.manifest); err != nil {
+		return err
+	}
+
+	if mgr.anonymizer != nil {
+		if err := config.SaveAnonymizationMapping(mgr.anonymizer.Mapping()); err != nil {
+			return err
+		}
+	}
+
+	if err := mgr.persister.Finalize(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// collected holds the redacted resources a collector captured
+type collected struct {
+	collector string
+	resources []collector.ClusterResource
+}
+
+// collect runs the collectors and redacts the resources selected by the
+// kind filter. Collectors run concurrently but their resources are kept in
+// collector order, which keeps the snapshot the same between runs
+func (mgr *Manager) collect(ctx context.Context) ([]collected, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var all []collected
+	for _, running := range mgr.collectAll(ctx) {
+		result := running.wait()
+		c := running.collector
+		if result.timedOut {
+			log.WithError(context.DeadlineExceeded).Warnw("Collector ran out of time, keeping what it collected", "collector", c.Name())
+			mgr.manifest.TimedOut = append(mgr.manifest.TimedOut, c.Name())
+
+			// Whatever stopped a collector past its deadline, it ran out of
+			// time rather than failing
+			if result.err != nil && !errors.As(result.err, new(collector.Failures)) {
+				result.err = collector.Failures{{Reason: "Timeout", Message: result.err.Error()}}
+			}
+		}
+		if err := mgr.addFailures(c, result.err); err != nil {
+			return nil, err
+		}
+
+		captured := collected{collector: c.Name()}
+		for _, resource := range result.resources {
+			if !mgr.kinds.Matches(c.Name(), resource.Kind) {
+				// What is left out may still name pods and nodes that
+				// captured resources mention
+				mgr.learn(resource)
+				continue
+			}
+
+			resource, hits, err := mgr.redact(resource)
+			if err != nil {
+				return nil, err
+			}
+
+			if resource.Kind == "log" {
+				mgr.report.AddLog(resource.Metadata["namespace"], resource.Metadata["pod"], resource.Name, hits)
+			} else {
+				mgr.report.AddResource(resource.Kind, resource.Metadata["namespace"], resource.Name, hits)
+			}
+
+			mgr.learn(resource)
+			captured.resources = append(captured.resources, resource)
+		}
+		all = append(all, captured)
+	}
+
+	return all, nil
+}
+
+// addFailures records what a collector failed to collect, failures of kinds
+// that are not captured anyway are left out. Any other error is returned
+func (mgr *Manager) addFailures(c collector.Collector, err error) error {
+	var failures collector.Failures
+	if err != nil && !errors.As(err, &failures) {
+		return err
+	}
+
+	for _, failure := range failures {
+		// Failures without a kind concern the whole collector
+		if failure.Kind != "" && !mgr.kinds.Matches(c.Name(), failure.Kind) {
+			continue
+		}
+
+		failure.Collector = c.Name()
+		mgr.addFailure(failure)
+	}
+
+	return nil
+}
+
+// addFailure records a part of the cluster that could not be collected
+func (mgr *Manager) addFailure(failure *collector.Failure) {
+	log.WithError(failure).Warnw("Failed to collect", "collector", failure.Collector, "kind", failure.Kind, "namespace", failure.Namespace, "reason", failure.Reason)
+	mgr.failures = append(mgr.failures, failure)
+}
+
+// Failures returns what could not be collected for the last snapshot
+func (mgr *Manager) Failures() collector.Failures {
+	return mgr.failures
+}
+
+// collectorResult is the outcome of running one collector
+type collectorResult struct {
+	resources []collector.ClusterResource
+	err       error
+	// timedOut is set when the deadline of the collector had passed by
+	// the time it returned
+	timedOut bool
+}
+
+// runningCollector delivers the result of a collector once it is done
+type runningCollector struct {
+	collector collector.Collector
+	ctx       context.Context
+	done      chan collectorResult
+}
+
+// wait returns the result of the collector. Past its deadline the collector
+// gets a grace period to return what it has, after that it is abandoned
+func (r runningCollector) wait() collectorResult {
+	select {
+	case result := <-r.done:
+		return result
+	case <-r.ctx.Done():
+	}
+
+	select {
+	case result := <-r.done:
+		return result
+	case <-time.After(collectorGracePeriod):
+		return collectorResult{timedOut: true, err: collector.Failures{{
+			Reason:  "Timeout",
+			Message: fmt.Sprintf("collector %s did not stop within %s of its deadline", r.collector.Name(), collectorGracePeriod),
+		}}}
+	}
+}
+
+// collectAll starts every selected collector at once and returns them in
+// collector order
+func (mgr *Manager) collectAll(ctx context.Context) []runningCollector {
+	var running []runningCollector
+
+	// Every collector works on as many items at once as the client lets
+	// through, more would only wait for the client
+	ctx = collector.WithWorkers(ctx, mgr.concurrency)
+
 	for _, c := range mgr.collectors {
-		resources, err := c.Collect(ctx)
+		if !mgr.kinds.Runs(c) {
+			log.Debug("Skipping collector, none of its kinds are selected", "collector", c.Name())
+			continue
+		}
+
+		collectorCtx, cancel := ctx, context.CancelFunc(func() {})
+		if timeout := mgr.timeoutOf(c); timeout > 0 {
+			collectorCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+
+		// Buffered so a collector finishing after an earlier failure or
+		// after being abandoned does not block forever
+		done := make(chan collectorResult, 1)
+		go func() {
+			defer cancel()
+			resources, err := c.Collect(collectorCtx)
+			// Checked before the cancel above, which would hide the deadline
+			timedOut := errors.Is(collectorCtx.Err(), context.DeadlineExceeded)
+			done <- collectorResult{resources: resources, err: err, timedOut: timedOut}
+		}()
+
+		running = append(running, runningCollector{collector: c, ctx: collectorCtx, done: done})
+	}
+
+	return running
+}
+
+// logPage reports the progress of lists that span several pages
+func logPage(progress kube.PageProgress) {
+	fields := []any{"resource", progress.Resource, "page", progress.Page, "items", progress.Items}
+	if progress.Namespace != "" {
+		fields = append(fields, "namespace", progress.Namespace)
+	}
+	if progress.Remaining != nil {
+		fields = append(fields, "remaining", *progress.Remaining)
+	}
+	log.Debug("Read page", fields...)
+}
+
+// RedactionReport returns what was redacted from the last snapshot
+func (mgr *Manager) RedactionReport() *redact.Report {
+	return mgr.report
+}
+
+// redact removes sensitive values from a resource before it is persisted,
+// logs are scrubbed and everything else goes through the redaction rules
+func (mgr *Manager) redact(resource collector.ClusterResource) (collector.ClusterResource, redact.Hits, error) {
+	if raw, ok := resource.Data.([]byte); ok {
+		if resource.Kind != "log" {
+			return resource, nil, nil
+		}
+
+		var scrubbed bytes.Buffer
+		hits, err := mgr.scrubber.Scrub(bytes.NewReader(raw), &scrubbed)
 		if err != nil {
-			return err
+			return resource, nil, fmt.Errorf("failed to scrub log %s: %w", resource.Name, err)
 		}
+		resource.Data = scrubbed.Bytes()
 
-		for _, resource := range resources {
-			mgr.persister.Persist(resource)
+		return resource, hits, nil
+	}
+
+	data, hits, err := mgr.redactor.Redact(resource.Kind, resource.Data)
+	if err != nil {
+		return resource, nil, fmt.Errorf("failed to redact %s %s: %w", resource.Kind, resource.Name, err)
+	}
+	resource.Data = data
+
+	return resource, hits, nil
+}
+
+// learn registers the identifiers of a resource with the anonymizer, those
+// in its data only once it is redacted and no longer typed
+func (mgr *Manager) learn(resource collector.ClusterResource) {
+	if mgr.anonymizer == nil {
+		return
+	}
+
+	mgr.anonymizer.LearnMetadata(resource.Metadata)
+	mgr.anonymizer.Learn(resource.Kind, resource.Data)
+}
+
+// anonymize replaces the identifiers of a resource, its name and metadata
+// included so file paths are anonymized too
+func (mgr *Manager) anonymize(resource collector.ClusterResource) (collector.ClusterResource, error) {
+	if mgr.anonymizer == nil {
+		return resource, nil
+	}
+
+	if raw, ok := resource.Data.([]byte); ok {
+		resource.Data = []byte(mgr.anonymizer.Text(string(raw)))
+	} else {
+		data, err := mgr.anonymizer.Data(resource.Data)
+		if err != nil {
+			return resource, fmt.Errorf("failed to anonymize %s %s: %w", resource.Kind, resource.Name, err)
 		}
+		resource.Data = data
+	}
+
+	resource.Name = mgr.anonymizer.Text(resource.Name)
+
+	metadata := make(map[string]string, len(resource.Metadata))
+	for key, value := range resource.Metadata {
+		metadata[key] = mgr.anonymizer.Text(value)
 	}
+	resource.Metadata = metadata
 
-    if err := mgr.persister.Finalize(); err != nil {
-        return err
-    }
+	return resource, nil
+}
+
+// persistFile stores a top level file of the snapshot, anonymized when
+// anonymization is enabled
+func (mgr *Manager) persistFile(name string, data any) error {
+	if mgr.anonymizer != nil {
+		anonymized, err := mgr.anonymizer.Data(data)
+		if err != nil {
+			return fmt.Errorf("failed to anonymize %s: %w", name, err)
+		}
+		data = anonymized
+	}
 
-    return nil
+	return mgr.persister.PersistFile(name, data)
 }
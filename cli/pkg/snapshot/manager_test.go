@@ -0,0 +1,162 @@
+package snapshot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/3nd3r1/kubin/cli/pkg/collector"
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+	"github.com/3nd3r1/kubin/cli/pkg/redact"
+	"github.com/stretchr/testify/assert"
+)
+
+// slowCollector collects one widget and then waits until it is cancelled
+type slowCollector struct{}
+
+func (c *slowCollector) Name() string {
+	return "slow"
+}
+
+func (c *slowCollector) Kinds() []string {
+	return []string{"widget"}
+}
+
+func (c *slowCollector) Collect(ctx context.Context) ([]collector.ClusterResource, error) {
+	resources := []collector.ClusterResource{{Kind: "widget", Name: "first"}}
+
+	<-ctx.Done()
+
+	return resources, collector.Failures{{Kind: "widget", Reason: "Timeout", Message: ctx.Err().Error()}}
+}
+
+func TestManager_CollectorTimeout(t *testing.T) {
+	mgr := &Manager{
+		collectors:       []collector.Collector{&slowCollector{}},
+		collectorTimeout: 20 * time.Millisecond,
+	}
+
+	running := mgr.collectAll(context.Background())
+	assert.Len(t, running, 1)
+
+	result := running[0].wait()
+
+	// The collector is cancelled and what it collected so far is kept
+	assert.True(t, result.timedOut)
+	assert.Len(t, result.resources, 1)
+	var failures collector.Failures
+	assert.ErrorAs(t, result.err, &failures)
+	assert.Equal(t, "Timeout", failures[0].Reason)
+}
+
+// newRateLimitedClient returns a client for an API server serving a single
+// namespace, allowing one request and then one every ten seconds
+func newRateLimitedClient(t *testing.T) kube.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"kind":"NamespaceList","apiVersion":"v1","metadata":{},"items":[{"metadata":{"name":"shop"}}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	content := `apiVersion: v1
+kind: Config
+current-context: test
+clusters:
+- name: test
+  cluster:
+    server: ` + server.URL + `
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+users:
+- name: test
+  user: {}
+`
+	assert.NoError(t, os.WriteFile(kubeconfig, []byte(content), 0o600))
+
+	client, err := kube.NewKubeClient(kube.ClientOptions{Kubeconfig: kubeconfig, QPS: 0.1, Burst: 1})
+	assert.NoError(t, err)
+
+	return kube.NewLimitedClient(client, 2)
+}
+
+func TestManager_CollectorRateLimited(t *testing.T) {
+	redactor, err := redact.NewRedactor(nil)
+	assert.NoError(t, err)
+	mgr := &Manager{
+		redactor:         redactor,
+		collectors:       []collector.Collector{collector.NewCoreCollector(newRateLimitedClient(t), collector.KindFilter{})},
+		collectorTimeout: time.Second,
+		report:           redact.NewReport(),
+	}
+
+	collected, err := mgr.collect(context.Background())
+
+	// The rate limiter turns the pods down as they could not be listed in
+	// time, the namespaces listed before are kept
+	assert.NoError(t, err)
+	assert.Len(t, collected[0].resources, 1)
+	assert.Equal(t, "namespace", collected[0].resources[0].Kind)
+	assert.Len(t, mgr.failures, 1)
+	assert.Equal(t, "pod", mgr.failures[0].Kind)
+	assert.Equal(t, "Timeout", mgr.failures[0].Reason)
+}
+
+func TestManager_TimeoutOf(t *testing.T) {
+	mgr := &Manager{
+		collectorTimeout:  5 * time.Minute,
+		collectorTimeouts: map[string]time.Duration{"slow": 30 * time.Second},
+	}
+
+	assert.Equal(t, 30*time.Second, mgr.timeoutOf(&slowCollector{}))
+	assert.Equal(t, 5*time.Minute, mgr.timeoutOf(collector.NewCoreCollector(nil, collector.KindFilter{})))
+}
+
+// logOnlyCollector returns the log of a pod that no captured resource names
+type logOnlyCollector struct{}
+
+func (c *logOnlyCollector) Name() string {
+	return "logs"
+}
+
+func (c *logOnlyCollector) Kinds() []string {
+	return []string{"log"}
+}
+
+func (c *logOnlyCollector) Collect(ctx context.Context) ([]collector.ClusterResource, error) {
+	return []collector.ClusterResource{{
+		Kind:     "log",
+		Name:     "api-7d9f8-x2x9q/app.log",
+		Data:     []byte("api-7d9f8-x2x9q started"),
+		Metadata: map[string]string{"namespace": "payments", "pod": "api-7d9f8-x2x9q", "container": "app"},
+	}}, nil
+}
+
+func TestManager_AnonymizesPodsOnlyNamedByLogs(t *testing.T) {
+	scrubber, err := redact.NewScrubber(nil)
+	assert.NoError(t, err)
+	mgr := &Manager{
+		collectors: []collector.Collector{&logOnlyCollector{}},
+		scrubber:   scrubber,
+		report:     redact.NewReport(),
+		anonymizer: redact.NewAnonymizer([]byte("test-key")),
+	}
+
+	collected, err := mgr.collect(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, collected[0].resources, 1)
+
+	resource, err := mgr.anonymize(collected[0].resources[0])
+	assert.NoError(t, err)
+	assert.NotContains(t, resource.Name, "api-7d9f8-x2x9q")
+	assert.NotContains(t, resource.Metadata["pod"], "api-7d9f8-x2x9q")
+	assert.NotContains(t, resource.Metadata["namespace"], "payments")
+	assert.NotContains(t, string(resource.Data.([]byte)), "api-7d9f8-x2x9q")
+}
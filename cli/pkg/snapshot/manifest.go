@@ -0,0 +1,24 @@
+package snapshot
+
+import (
+	"time"
+
+	"github.com/3nd3r1/kubin/cli/pkg/collector"
+	"github.com/3nd3r1/kubin/cli/pkg/kube"
+)
+
+// Manifest describes how a snapshot was taken, so the viewer can tell a
+// partial snapshot from a complete one
+type Manifest struct {
+	CreatedAt time.Time `json:"createdAt"`
+	// Partial is set when part of the cluster was left out on purpose
+	Partial bool `json:"partial"`
+	// Incomplete is set when part of the cluster could not be collected,
+	// errors.json tells which part
+	Incomplete bool `json:"incomplete"`
+	// TimedOut names the collectors that ran out of time, what they
+	// collected until then is kept
+	TimedOut []string             `json:"timedOut,omitempty"`
+	Scope    kube.Scope           `json:"scope"`
+	Kinds    collector.KindFilter `json:"kinds"`
+}